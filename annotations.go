@@ -0,0 +1,390 @@
+package dify
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListAnnotations 分页获取标注列表，Keyword 不为空时按问题和答案搜索
+func (a *appClient) ListAnnotations(ctx context.Context, req *ListAnnotationsRequest) (*Response[ListAnnotationsResponse], error) {
+	var resp = &ListAnnotationsResponse{}
+	request := a.request().
+		WithContext(ctx).
+		SetResult(&resp)
+	if req.Page > 0 {
+		request.SetQueryParam("page", strconv.Itoa(req.Page))
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Keyword != "" {
+		request.SetQueryParam("keyword", req.Keyword)
+	}
+	response, err := request.Get("/apps/annotations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ListAnnotationsResponse](response, resp), nil
+}
+
+// CreateAnnotation 新增标注
+func (a *appClient) CreateAnnotation(ctx context.Context, req *CreateAnnotationRequest) (*Response[Annotation], error) {
+	var resp = &Annotation{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post("/apps/annotations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create annotation: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[Annotation](response, resp), nil
+}
+
+// UpdateAnnotation 修改标注
+func (a *appClient) UpdateAnnotation(ctx context.Context, req *UpdateAnnotationRequest) (*Response[Annotation], error) {
+	var resp = &Annotation{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Put(fmt.Sprintf("/apps/annotations/%s", req.AnnotationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update annotation: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[Annotation](response, resp), nil
+}
+
+// DeleteAnnotation 删除标注
+func (a *appClient) DeleteAnnotation(ctx context.Context, req *DeleteAnnotationRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetResult(&resp).
+		Delete(fmt.Sprintf("/apps/annotations/%s", req.AnnotationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete annotation: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// SetAnnotationReply 开启或关闭标注回复，返回异步任务，可使用 WaitAnnotationReplyJob 等待完成
+func (a *appClient) SetAnnotationReply(ctx context.Context, req *SetAnnotationReplyRequest) (*Response[AnnotationReplyJob], error) {
+	var resp = &AnnotationReplyJob{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post(fmt.Sprintf("/apps/annotation-reply/%s", req.Action()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set annotation reply: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[AnnotationReplyJob](response, resp), nil
+}
+
+// GetAnnotationReplyJob 查询开启/关闭标注回复任务的状态
+func (a *appClient) GetAnnotationReplyJob(ctx context.Context, req *GetAnnotationReplyJobRequest) (*Response[AnnotationReplyJob], error) {
+	var resp = &AnnotationReplyJob{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get(fmt.Sprintf("/apps/annotation-reply/%s/status/%s", req.Action, req.JobID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotation reply job: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[AnnotationReplyJob](response, resp), nil
+}
+
+// WaitAnnotationReplyJob 轮询开启/关闭标注回复任务直到完成，任务失败时返回错误
+func (a *appClient) WaitAnnotationReplyJob(ctx context.Context, req *GetAnnotationReplyJobRequest, interval time.Duration) (*AnnotationReplyJob, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, err := a.GetAnnotationReplyJob(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.IsSuccess() {
+			return nil, fmt.Errorf("failed to get annotation reply job with status %d: %s", resp.StatusCode(), resp.Message)
+		}
+		switch resp.Result.JobStatus {
+		case AnnotationJobStatusCompleted:
+			return resp.Result, nil
+		case AnnotationJobStatusError:
+			return resp.Result, fmt.Errorf("annotation reply job %s failed: %s", req.JobID, resp.Result.ErrorMsg)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListAllAnnotations 获取全部标注
+func (a *appClient) ListAllAnnotations(ctx context.Context) ([]Annotation, error) {
+	var annotations []Annotation
+	for page := 1; ; page++ {
+		resp, err := a.ListAnnotations(ctx, &ListAnnotationsRequest{Page: page, Limit: 100})
+		if err != nil {
+			return nil, err
+		}
+		if !resp.IsSuccess() {
+			return nil, fmt.Errorf("failed to list annotations with status %d: %s", resp.StatusCode(), resp.Message)
+		}
+		annotations = append(annotations, resp.Result.Data...)
+		// 空页时停止，避免服务端在没有数据时仍返回 has_more 导致死循环
+		if !resp.Result.HasMore || len(resp.Result.Data) == 0 {
+			return annotations, nil
+		}
+	}
+}
+
+// ExportAnnotationsCSV 把全部标注导出为 CSV，表头为 question,answer
+func (a *appClient) ExportAnnotationsCSV(ctx context.Context, w io.Writer) error {
+	annotations, err := a.ListAllAnnotations(ctx)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"question", "answer"}); err != nil {
+		return err
+	}
+	for _, annotation := range annotations {
+		if err := writer.Write([]string{annotation.Question, annotation.Answer}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportAnnotationsCSV 从 CSV 同步标注，CSV 的前两列为问题和答案，首行为表头
+// 按问题匹配已有标注：不存在时新增，答案不同时修改，相同时跳过，因此可以重复执行
+// 单条失败不会中断导入，失败的行记录在 AnnotationImportReport.Failed 中
+func (a *appClient) ImportAnnotationsCSV(ctx context.Context, r io.Reader) (*AnnotationImportReport, error) {
+	rows, err := readAnnotationsCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := a.ListAllAnnotations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byQuestion := make(map[string]Annotation, len(existing))
+	for _, annotation := range existing {
+		byQuestion[strings.TrimSpace(annotation.Question)] = annotation
+	}
+
+	report := &AnnotationImportReport{}
+	for _, row := range rows {
+		current, ok := byQuestion[row.Question]
+		switch {
+		case !ok:
+			resp, err := a.CreateAnnotation(ctx, &CreateAnnotationRequest{Question: row.Question, Answer: row.Answer})
+			if err = annotationResult(resp, err); err != nil {
+				report.Failed = append(report.Failed, AnnotationImportFailure{Line: row.Line, Question: row.Question, Err: err})
+				continue
+			}
+			byQuestion[row.Question] = *resp.Result
+			report.Created++
+		case strings.TrimSpace(current.Answer) != row.Answer:
+			resp, err := a.UpdateAnnotation(ctx, &UpdateAnnotationRequest{AnnotationID: current.ID, Question: row.Question, Answer: row.Answer})
+			if err = annotationResult(resp, err); err != nil {
+				report.Failed = append(report.Failed, AnnotationImportFailure{Line: row.Line, Question: row.Question, Err: err})
+				continue
+			}
+			byQuestion[row.Question] = *resp.Result
+			report.Updated++
+		default:
+			report.Unchanged++
+		}
+	}
+	return report, nil
+}
+
+func annotationResult(resp *Response[Annotation], err error) error {
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("status %d: %s", resp.StatusCode(), resp.Message)
+	}
+	return nil
+}
+
+type annotationRow struct {
+	Line     int
+	Question string
+	Answer   string
+}
+
+// readAnnotationsCSV 读取 CSV，跳过表头和问题为空的行，重复的问题以最后一行为准
+func readAnnotationsCSV(r io.Reader) ([]annotationRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	var rows []annotationRow
+	index := map[string]int{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read annotations CSV: %w", err)
+		}
+		if line == 1 {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("annotations CSV line %d: expected question and answer columns", line)
+		}
+		question := strings.TrimSpace(record[0])
+		if question == "" {
+			continue
+		}
+		row := annotationRow{Line: line, Question: question, Answer: strings.TrimSpace(record[1])}
+		if i, ok := index[question]; ok {
+			rows[i] = row
+			continue
+		}
+		index[question] = len(rows)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// AnnotationReplyAction 标注回复开关
+type AnnotationReplyAction string
+
+const (
+	AnnotationReplyActionEnable  AnnotationReplyAction = "enable"
+	AnnotationReplyActionDisable AnnotationReplyAction = "disable"
+)
+
+// AnnotationJobStatus 标注回复任务状态
+type AnnotationJobStatus string
+
+const (
+	AnnotationJobStatusWaiting    AnnotationJobStatus = "waiting"
+	AnnotationJobStatusProcessing AnnotationJobStatus = "processing"
+	AnnotationJobStatusCompleted  AnnotationJobStatus = "completed"
+	AnnotationJobStatusError      AnnotationJobStatus = "error"
+)
+
+// Annotation 标注
+type Annotation struct {
+	ID        string `json:"id"`
+	Question  string `json:"question"`
+	Answer    string `json:"answer"`
+	HitCount  int    `json:"hit_count"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// ListAnnotationsRequest 获取标注列表请求
+type ListAnnotationsRequest struct {
+	Page    int    // 页码，默认 1
+	Limit   int    // 每页数量，默认 20
+	Keyword string // 搜索关键词
+}
+
+// ListAnnotationsResponse 标注列表响应
+type ListAnnotationsResponse struct {
+	Data    []Annotation `json:"data"`
+	HasMore bool         `json:"has_more"`
+	Limit   int          `json:"limit"`
+	Total   int          `json:"total"`
+	Page    int          `json:"page"`
+}
+
+// CreateAnnotationRequest 新增标注请求
+type CreateAnnotationRequest struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// UpdateAnnotationRequest 修改标注请求
+type UpdateAnnotationRequest struct {
+	AnnotationID string `json:"-"` // 标注ID，不包含在JSON中
+	Question     string `json:"question"`
+	Answer       string `json:"answer"`
+}
+
+// DeleteAnnotationRequest 删除标注请求
+type DeleteAnnotationRequest struct {
+	AnnotationID string // 标注ID
+}
+
+// SetAnnotationReplyRequest 开启/关闭标注回复请求
+type SetAnnotationReplyRequest struct {
+	Enabled               bool    `json:"-"`                       // true 开启，false 关闭
+	EmbeddingProviderName string  `json:"embedding_provider_name"` // 嵌入模型供应商
+	EmbeddingModelName    string  `json:"embedding_model_name"`    // 嵌入模型
+	ScoreThreshold        float64 `json:"score_threshold"`         // 相似度阈值，超过阈值的标注才会被回复
+}
+
+// Action 返回请求对应的开关
+func (r *SetAnnotationReplyRequest) Action() AnnotationReplyAction {
+	if r.Enabled {
+		return AnnotationReplyActionEnable
+	}
+	return AnnotationReplyActionDisable
+}
+
+// AnnotationReplyJob 开启/关闭标注回复的异步任务
+type AnnotationReplyJob struct {
+	JobID     string              `json:"job_id"`
+	JobStatus AnnotationJobStatus `json:"job_status"`
+	ErrorMsg  string              `json:"error_msg"`
+}
+
+// GetAnnotationReplyJobRequest 查询标注回复任务请求
+type GetAnnotationReplyJobRequest struct {
+	Action AnnotationReplyAction // 任务对应的开关
+	JobID  string                // 任务ID
+}
+
+// AnnotationImportReport CSV 导入结果
+type AnnotationImportReport struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Failed    []AnnotationImportFailure
+}
+
+// AnnotationImportFailure 导入失败的行
+type AnnotationImportFailure struct {
+	Line     int    // CSV 行号，从 1 开始
+	Question string // 问题
+	Err      error
+}
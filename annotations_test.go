@@ -0,0 +1,122 @@
+package dify
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportAnnotationsCSV(t *testing.T) {
+	c, _ := newTestClient(t)
+	app := c.App(newTestAppToken(t, c))
+	ctx := context.Background()
+
+	csv := "question,answer\n" +
+		"如何重置密码？,在登录页点击忘记密码\n" +
+		"客服电话？,400-000-0000\n" +
+		",没有问题的行会被跳过\n"
+	report, err := app.ImportAnnotationsCSV(ctx, strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 2 || report.Updated != 0 || len(report.Failed) != 0 {
+		t.Fatalf("Unexpected first import report %+v", report)
+	}
+
+	// 再次导入时只修改答案变化的标注
+	csv = "question,answer\n" +
+		"如何重置密码？,在登录页点击忘记密码\n" +
+		"客服电话？,400-111-1111\n"
+	report, err = app.ImportAnnotationsCSV(ctx, strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 0 || report.Updated != 1 || report.Unchanged != 1 {
+		t.Fatalf("Unexpected second import report %+v", report)
+	}
+
+	var exported bytes.Buffer
+	if err := app.ExportAnnotationsCSV(ctx, &exported); err != nil {
+		t.Fatal(err)
+	}
+	want := "question,answer\n如何重置密码？,在登录页点击忘记密码\n客服电话？,400-111-1111\n"
+	if exported.String() != want {
+		t.Errorf("Expected %q, got %q", want, exported.String())
+	}
+}
+
+func TestImportAnnotationsCSVIgnoresStoredWhitespace(t *testing.T) {
+	c, _ := newTestClient(t)
+	app := c.App(newTestAppToken(t, c))
+	ctx := context.Background()
+
+	// 控制台编辑的答案常带有末尾换行
+	if _, err := app.CreateAnnotation(ctx, &CreateAnnotationRequest{Question: "营业时间？", Answer: "9:00-18:00\n"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		report, err := app.ImportAnnotationsCSV(ctx, strings.NewReader("question,answer\n营业时间？,9:00-18:00 \n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Unchanged != 1 || report.Updated != 0 || report.Created != 0 {
+			t.Fatalf("Run %d: expected the annotation to be unchanged, got %+v", i, report)
+		}
+	}
+}
+
+func TestListAllAnnotationsStopsOnEmptyPage(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"a-1","question":"q","answer":"a"}],"has_more":true,"limit":100,"total":1,"page":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[],"has_more":true,"limit":100,"total":1,"page":2}`))
+	}))
+	defer server.Close()
+
+	annotations, err := NewAppClient(server.URL, "app-key").ListAllAnnotations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(annotations) != 1 || calls != 2 {
+		t.Errorf("Expected 1 annotation in 2 requests, got %d in %d", len(annotations), calls)
+	}
+}
+
+func TestAnnotationReply(t *testing.T) {
+	c, _ := newTestClient(t)
+	app := c.App(newTestAppToken(t, c))
+	ctx := context.Background()
+
+	resp, err := app.SetAnnotationReply(ctx, &SetAnnotationReplyRequest{
+		Enabled:               true,
+		EmbeddingProviderName: "openai",
+		EmbeddingModelName:    "text-embedding-3-small",
+		ScoreThreshold:        0.9,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal(resp.Message)
+	}
+
+	job, err := app.WaitAnnotationReplyJob(ctx, &GetAnnotationReplyJobRequest{
+		Action: AnnotationReplyActionEnable,
+		JobID:  resp.Result.JobID,
+	}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.JobStatus != AnnotationJobStatusCompleted {
+		t.Errorf("Expected completed job, got %s", job.JobStatus)
+	}
+}
@@ -0,0 +1,181 @@
+package dify
+
+import (
+	"context"
+	"io"
+	"resty.dev/v3"
+	"time"
+)
+
+// AppClient 绑定了应用 API 密钥的应用运行时客户端
+// 请求中的 Token 字段会被忽略，始终使用绑定的密钥
+type AppClient interface {
+	// App
+
+	// GetAppParameters 获取应用的参数配置，包括用户输入表单、文件上传设置和系统参数
+	GetAppParameters(ctx context.Context) (*Response[AppParameters], error)
+	// GetAppInfo 获取应用的基本信息
+	GetAppInfo(ctx context.Context) (*Response[AppInfo], error)
+	// GetAppMeta 获取应用的元信息（工具图标）
+	GetAppMeta(ctx context.Context) (*Response[AppMeta], error)
+	// GetAppSite 获取应用的 WebApp 设置
+	GetAppSite(ctx context.Context) (*Response[AppSite], error)
+
+	// Workflow
+
+	// CallWorkflowAppBlocking 调用工作流应用，返回阻塞响应
+	CallWorkflowAppBlocking(ctx context.Context, req *CallWorkflowRequest) (*Response[CallWorkflowCompletionResponse], error)
+	// CallWorkflowAppStreaming 调用工作流应用，返回 SSE 流
+	CallWorkflowAppStreaming(ctx context.Context, req *CallWorkflowRequest) (chan *CallWorkflowChunkCompletionResponse, error)
+	// GetWorkflowRun 获取工作流执行情况
+	GetWorkflowRun(ctx context.Context, req *GetWorkflowRunRequest) (*Response[WorkflowRun], error)
+	// ListWorkflowLogs 分页获取工作流日志
+	ListWorkflowLogs(ctx context.Context, req *ListWorkflowLogsRequest) (*Response[ListWorkflowLogsResponse], error)
+
+	// Completion
+
+	// SendCompletionMessage 发送文本生成消息，返回阻塞响应
+	SendCompletionMessage(ctx context.Context, req *SendCompletionMessageRequest) (*Response[CompletionMessageResponse], error)
+	// SendCompletionMessageStreaming 发送文本生成消息，返回 SSE 流
+	SendCompletionMessageStreaming(ctx context.Context, req *SendCompletionMessageRequest) (chan *CompletionMessageChunk, error)
+
+	// Chat
+
+	// SendChatMessage 发送对话消息，返回阻塞响应
+	SendChatMessage(ctx context.Context, req *SendChatMessageRequest) (*Response[ChatMessageResponse], error)
+	// SendChatMessageStreaming 发送对话消息，返回 SSE 流
+	SendChatMessageStreaming(ctx context.Context, req *SendChatMessageRequest) (chan *ChatMessageChunk, error)
+
+	// Conversations
+
+	// ListConversations 分页获取用户的会话列表
+	ListConversations(ctx context.Context, req *ListConversationsRequest) (*Response[ListConversationsResponse], error)
+	// ListConversationMessages 分页获取会话的历史消息，按时间倒序
+	ListConversationMessages(ctx context.Context, req *ListConversationMessagesRequest) (*Response[ListConversationMessagesResponse], error)
+	// RenameConversation 重命名会话
+	RenameConversation(ctx context.Context, req *RenameConversationRequest) (*Response[Conversation], error)
+	// DeleteConversation 删除会话
+	DeleteConversation(ctx context.Context, req *DeleteConversationRequest) (*Response[ResultResponse], error)
+	// ListConversationVariables 获取会话变量
+	ListConversationVariables(ctx context.Context, req *ListConversationVariablesRequest) (*Response[ListConversationVariablesResponse], error)
+
+	// Files
+
+	// UploadFile 上传文件，用于对话消息或工作流的文件输入
+	UploadFile(ctx context.Context, req *UploadFileRequest) (*Response[UploadFileResponse], error)
+
+	// Audio
+
+	// AudioToText 语音转文字
+	AudioToText(ctx context.Context, req *AudioToTextRequest) (*Response[AudioToTextResponse], error)
+	// TextToAudio 文字转语音，返回语音数据，调用方负责关闭
+	TextToAudio(ctx context.Context, req *TextToAudioRequest) (io.ReadCloser, error)
+
+	// Annotations
+
+	// ListAnnotations 分页获取标注列表，支持关键词搜索
+	ListAnnotations(ctx context.Context, req *ListAnnotationsRequest) (*Response[ListAnnotationsResponse], error)
+	// ListAllAnnotations 获取全部标注
+	ListAllAnnotations(ctx context.Context) ([]Annotation, error)
+	// CreateAnnotation 新增标注
+	CreateAnnotation(ctx context.Context, req *CreateAnnotationRequest) (*Response[Annotation], error)
+	// UpdateAnnotation 修改标注
+	UpdateAnnotation(ctx context.Context, req *UpdateAnnotationRequest) (*Response[Annotation], error)
+	// DeleteAnnotation 删除标注
+	DeleteAnnotation(ctx context.Context, req *DeleteAnnotationRequest) (*Response[ResultResponse], error)
+	// SetAnnotationReply 开启或关闭标注回复，返回异步任务
+	SetAnnotationReply(ctx context.Context, req *SetAnnotationReplyRequest) (*Response[AnnotationReplyJob], error)
+	// GetAnnotationReplyJob 查询开启/关闭标注回复任务的状态
+	GetAnnotationReplyJob(ctx context.Context, req *GetAnnotationReplyJobRequest) (*Response[AnnotationReplyJob], error)
+	// WaitAnnotationReplyJob 轮询开启/关闭标注回复任务直到完成
+	WaitAnnotationReplyJob(ctx context.Context, req *GetAnnotationReplyJobRequest, interval time.Duration) (*AnnotationReplyJob, error)
+	// ImportAnnotationsCSV 从 CSV（question,answer）同步标注，可重复执行
+	ImportAnnotationsCSV(ctx context.Context, r io.Reader) (*AnnotationImportReport, error)
+	// ExportAnnotationsCSV 把全部标注导出为 CSV（question,answer）
+	ExportAnnotationsCSV(ctx context.Context, w io.Writer) error
+
+	// Messages
+
+	// SendMessageFeedback 消息反馈（点赞/点踩/撤销）
+	SendMessageFeedback(ctx context.Context, req *SendMessageFeedbackRequest) (*Response[ResultResponse], error)
+	// GetSuggestedQuestions 获取下一轮建议问题列表
+	GetSuggestedQuestions(ctx context.Context, req *GetSuggestedQuestionsRequest) (*Response[GetSuggestedQuestionsResponse], error)
+	// ListAppFeedbacks 分页获取应用的消息反馈列表
+	ListAppFeedbacks(ctx context.Context, req *ListAppFeedbacksRequest) (*Response[ListAppFeedbacksResponse], error)
+}
+
+// NewAppClient 创建只使用应用 API 密钥的客户端，不需要 console 登录
+func NewAppClient(baseUrl, appKey string) AppClient {
+	return &appClient{
+		httpClient: newAppClient(baseUrl),
+		appKey:     appKey,
+	}
+}
+
+type appClient struct {
+	httpClient *resty.Client
+	appKey     string
+}
+
+// request returns a request for app API (/v1/) authorized by the bound app API key
+func (a *appClient) request() *resty.Request {
+	return a.httpClient.R().SetHeader("Authorization", "Bearer "+a.appKey)
+}
+
+// App 返回绑定 appKey 的应用运行时客户端，与 Client 共享连接
+func (c *client) App(appKey string) AppClient {
+	return &appClient{
+		httpClient: c.appClient,
+		appKey:     appKey,
+	}
+}
+
+// 以下方法保留按请求传入 Token 的用法
+
+func (c *client) CallWorkflowAppBlocking(ctx context.Context, req *CallWorkflowRequest) (*Response[CallWorkflowCompletionResponse], error) {
+	return c.App(req.Token).CallWorkflowAppBlocking(ctx, req)
+}
+
+func (c *client) CallWorkflowAppStreaming(ctx context.Context, req *CallWorkflowRequest) (chan *CallWorkflowChunkCompletionResponse, error) {
+	return c.App(req.Token).CallWorkflowAppStreaming(ctx, req)
+}
+
+func (c *client) GetWorkflowRun(ctx context.Context, req *GetWorkflowRunRequest) (*Response[WorkflowRun], error) {
+	return c.App(req.Token).GetWorkflowRun(ctx, req)
+}
+
+func (c *client) ListWorkflowLogs(ctx context.Context, req *ListWorkflowLogsRequest) (*Response[ListWorkflowLogsResponse], error) {
+	return c.App(req.Token).ListWorkflowLogs(ctx, req)
+}
+
+func (c *client) SendCompletionMessage(ctx context.Context, req *SendCompletionMessageRequest) (*Response[CompletionMessageResponse], error) {
+	return c.App(req.Token).SendCompletionMessage(ctx, req)
+}
+
+func (c *client) SendCompletionMessageStreaming(ctx context.Context, req *SendCompletionMessageRequest) (chan *CompletionMessageChunk, error) {
+	return c.App(req.Token).SendCompletionMessageStreaming(ctx, req)
+}
+
+func (c *client) SendChatMessage(ctx context.Context, req *SendChatMessageRequest) (*Response[ChatMessageResponse], error) {
+	return c.App(req.Token).SendChatMessage(ctx, req)
+}
+
+func (c *client) SendChatMessageStreaming(ctx context.Context, req *SendChatMessageRequest) (chan *ChatMessageChunk, error) {
+	return c.App(req.Token).SendChatMessageStreaming(ctx, req)
+}
+
+func (c *client) UploadFile(ctx context.Context, req *UploadFileRequest) (*Response[UploadFileResponse], error) {
+	return c.App(req.Token).UploadFile(ctx, req)
+}
+
+func (c *client) SendMessageFeedback(ctx context.Context, req *SendMessageFeedbackRequest) (*Response[ResultResponse], error) {
+	return c.App(req.Token).SendMessageFeedback(ctx, req)
+}
+
+func (c *client) GetSuggestedQuestions(ctx context.Context, req *GetSuggestedQuestionsRequest) (*Response[GetSuggestedQuestionsResponse], error) {
+	return c.App(req.Token).GetSuggestedQuestions(ctx, req)
+}
+
+func (c *client) ListAppFeedbacks(ctx context.Context, req *ListAppFeedbacksRequest) (*Response[ListAppFeedbacksResponse], error) {
+	return c.App(req.Token).ListAppFeedbacks(ctx, req)
+}
@@ -0,0 +1,73 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAppClientWithoutLogin(t *testing.T) {
+	c, server := newTestClient(t)
+	token := newTestAppToken(t, c)
+
+	app := NewAppClient(server.URL, token)
+	resp, err := app.CallWorkflowAppBlocking(context.Background(), &CallWorkflowRequest{
+		Inputs: map[string]interface{}{"question": "hi"},
+		User:   "test_user",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result.Data.Outputs["question"] != "hi" {
+		t.Errorf("Unexpected outputs %v", resp.Result.Data.Outputs)
+	}
+
+	requests := server.Requests()
+	last := requests[len(requests)-1]
+	if last.Path != "/v1/workflows/run" || last.Authorization != "Bearer "+token {
+		t.Errorf("Expected the bound app key on the workflow run, got %+v", last)
+	}
+}
+
+func TestCallWorkflowRequestOmitsToken(t *testing.T) {
+	data, err := json.Marshal(&CallWorkflowRequest{User: "u", Token: "app-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "app-secret") {
+		t.Errorf("Token must not be serialized, got %s", data)
+	}
+}
+
+func TestListConversations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/conversations" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.RawQuery; got != "last_id=c-1&limit=2&sort_by=-created_at&user=u" {
+			t.Errorf("Unexpected query %s", got)
+		}
+		if r.Header.Get("Authorization") != "Bearer app-key" {
+			t.Errorf("Unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"limit":2,"has_more":true,"data":[{"id":"c-2","name":"hello","status":"normal"}]}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewAppClient(server.URL, "app-key").ListConversations(context.Background(), &ListConversationsRequest{
+		User:   "u",
+		LastID: "c-1",
+		Limit:  2,
+		SortBy: ConversationSortByCreatedAtDesc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Result.HasMore || len(resp.Result.Data) != 1 || resp.Result.Data[0].Name != "hello" {
+		t.Errorf("Unexpected response %+v", resp.Result)
+	}
+}
@@ -0,0 +1,212 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GetAppParameters 获取应用的参数配置，包括用户输入表单、文件上传设置和系统参数
+func (a *appClient) GetAppParameters(ctx context.Context) (*Response[AppParameters], error) {
+	var resp = &AppParameters{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get("/parameters")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app parameters: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[AppParameters](response, resp), nil
+}
+
+// GetAppInfo 获取应用的基本信息
+func (a *appClient) GetAppInfo(ctx context.Context) (*Response[AppInfo], error) {
+	var resp = &AppInfo{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get("/info")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app info: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[AppInfo](response, resp), nil
+}
+
+// GetAppMeta 获取应用的元信息（工具图标）
+func (a *appClient) GetAppMeta(ctx context.Context) (*Response[AppMeta], error) {
+	var resp = &AppMeta{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get("/meta")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app meta: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[AppMeta](response, resp), nil
+}
+
+// GetAppSite 获取应用的 WebApp 设置
+func (a *appClient) GetAppSite(ctx context.Context) (*Response[AppSite], error) {
+	var resp = &AppSite{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get("/site")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app site: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[AppSite](response, resp), nil
+}
+
+// AppParameters 应用参数配置
+type AppParameters struct {
+	OpeningStatement              string                              `json:"opening_statement"`   // 开场白
+	SuggestedQuestions            []string                            `json:"suggested_questions"` // 开场推荐问题
+	SuggestedQuestionsAfterAnswer SuggestedQuestionsAfterAnswerConfig `json:"suggested_questions_after_answer"`
+	SpeechToText                  SpeechToTextConfig                  `json:"speech_to_text"`
+	TextToSpeech                  TextToSpeechConfig                  `json:"text_to_speech"`
+	RetrieverResource             RetrieverResourceConfig             `json:"retriever_resource"`
+	AnnotationReply               AnnotationReplyConfig               `json:"annotation_reply"`
+	MoreLikeThis                  MoreLikeThisConfig                  `json:"more_like_this"`
+	SensitiveWordAvoidance        SensitiveWordAvoidanceConfig        `json:"sensitive_word_avoidance"`
+	UserInputForm                 []UserInputField                    `json:"user_input_form"` // 用户输入表单，按顺序渲染
+	FileUpload                    FileUploadConfig                    `json:"file_upload"`
+	SystemParameters              SystemParameters                    `json:"system_parameters"`
+}
+
+type AnnotationReplyConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SystemParameters 系统参数，文件大小限制的单位为 MB
+type SystemParameters struct {
+	FileSizeLimit           int `json:"file_size_limit"`
+	ImageFileSizeLimit      int `json:"image_file_size_limit"`
+	AudioFileSizeLimit      int `json:"audio_file_size_limit"`
+	VideoFileSizeLimit      int `json:"video_file_size_limit"`
+	WorkflowFileUploadLimit int `json:"workflow_file_upload_limit"` // 工作流单次可上传的文件数量
+}
+
+// InputFieldType 用户输入表单控件类型
+type InputFieldType string
+
+const (
+	InputFieldTypeTextInput InputFieldType = "text-input" // 单行文本
+	InputFieldTypeParagraph InputFieldType = "paragraph"  // 段落
+	InputFieldTypeSelect    InputFieldType = "select"     // 下拉选项
+	InputFieldTypeNumber    InputFieldType = "number"     // 数字
+	InputFieldTypeFile      InputFieldType = "file"       // 单文件
+	InputFieldTypeFileList  InputFieldType = "file-list"  // 文件列表
+)
+
+// UserInputField 用户输入表单中的一个控件
+// 接口返回形如 {"text-input": {...}} 的结构，控件类型保存在 Type 中
+type UserInputField struct {
+	Type                     InputFieldType   `json:"-"`
+	Label                    string           `json:"label"`
+	Variable                 string           `json:"variable"` // 调用时 inputs 中的键
+	Required                 bool             `json:"required"`
+	Default                  interface{}      `json:"default,omitempty"`
+	MaxLength                int              `json:"max_length,omitempty"` // 文本最大长度或文件列表最大数量
+	Options                  []string         `json:"options,omitempty"`    // select 的选项
+	AllowedFileTypes         []FileType       `json:"allowed_file_types,omitempty"`
+	AllowedFileExtensions    []string         `json:"allowed_file_extensions,omitempty"`
+	AllowedFileUploadMethods []TransferMethod `json:"allowed_file_upload_methods,omitempty"`
+}
+
+// userInputFieldBody 避免 MarshalJSON/UnmarshalJSON 递归
+type userInputFieldBody UserInputField
+
+func (f UserInputField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[InputFieldType]userInputFieldBody{f.Type: userInputFieldBody(f)})
+}
+
+func (f *UserInputField) UnmarshalJSON(data []byte) error {
+	var wrapper map[InputFieldType]userInputFieldBody
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+	if len(wrapper) != 1 {
+		return fmt.Errorf("user input field must have exactly one type, got %d", len(wrapper))
+	}
+	for fieldType, body := range wrapper {
+		*f = UserInputField(body)
+		f.Type = fieldType
+	}
+	return nil
+}
+
+// AppInfo 应用基本信息
+type AppInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Mode        string   `json:"mode"` // chat / advanced-chat / agent-chat / completion / workflow
+	AuthorName  string   `json:"author_name"`
+}
+
+// AppMeta 应用元信息
+type AppMeta struct {
+	ToolIcons map[string]ToolIcon `json:"tool_icons"` // 工具名称 -> 图标
+}
+
+// ToolIcon 工具图标，内置工具为图标地址，自定义工具为 emoji 及背景色
+type ToolIcon struct {
+	URL        string `json:"-"`
+	Content    string `json:"content,omitempty"`    // emoji
+	Background string `json:"background,omitempty"` // 背景色，如 #252525
+}
+
+// toolIconBody 避免 MarshalJSON/UnmarshalJSON 递归
+type toolIconBody ToolIcon
+
+func (i ToolIcon) MarshalJSON() ([]byte, error) {
+	if i.URL != "" {
+		return json.Marshal(i.URL)
+	}
+	return json.Marshal(toolIconBody(i))
+}
+
+func (i *ToolIcon) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*i = ToolIcon{URL: url}
+		return nil
+	}
+	var body toolIconBody
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	*i = ToolIcon(body)
+	return nil
+}
+
+// AppSite 应用的 WebApp 设置
+type AppSite struct {
+	Title                  string `json:"title"`
+	ChatColorTheme         string `json:"chat_color_theme"`
+	ChatColorThemeInverted bool   `json:"chat_color_theme_inverted"`
+	IconType               string `json:"icon_type"` // emoji / image
+	Icon                   string `json:"icon"`
+	IconBackground         string `json:"icon_background"`
+	IconURL                string `json:"icon_url"`
+	Description            string `json:"description"`
+	Copyright              string `json:"copyright"`
+	PrivacyPolicy          string `json:"privacy_policy"`
+	CustomDisclaimer       string `json:"custom_disclaimer"`
+	DefaultLanguage        string `json:"default_language"`
+	ShowWorkflowSteps      bool   `json:"show_workflow_steps"`
+	UseIconAsAnswerIcon    bool   `json:"use_icon_as_answer_icon"`
+}
@@ -0,0 +1,61 @@
+package dify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAppParametersUserInputForm(t *testing.T) {
+	data := `{
+		"opening_statement": "你好",
+		"user_input_form": [
+			{"text-input": {"label": "问题", "variable": "question", "required": true, "max_length": 48, "default": ""}},
+			{"select": {"label": "语言", "variable": "lang", "required": false, "options": ["zh", "en"], "default": "zh"}},
+			{"file-list": {"label": "附件", "variable": "files", "max_length": 3, "allowed_file_types": ["document"], "allowed_file_upload_methods": ["local_file"]}}
+		],
+		"system_parameters": {"file_size_limit": 15, "workflow_file_upload_limit": 10}
+	}`
+	var params AppParameters
+	if err := json.Unmarshal([]byte(data), &params); err != nil {
+		t.Fatal(err)
+	}
+	if len(params.UserInputForm) != 3 {
+		t.Fatalf("Expected 3 fields, got %d", len(params.UserInputForm))
+	}
+	question, lang, files := params.UserInputForm[0], params.UserInputForm[1], params.UserInputForm[2]
+	if question.Type != InputFieldTypeTextInput || question.Variable != "question" || !question.Required || question.MaxLength != 48 {
+		t.Errorf("Unexpected text input %+v", question)
+	}
+	if lang.Type != InputFieldTypeSelect || len(lang.Options) != 2 || lang.Default != "zh" {
+		t.Errorf("Unexpected select %+v", lang)
+	}
+	if files.Type != InputFieldTypeFileList || files.AllowedFileTypes[0] != FileTypeDocument || files.AllowedFileUploadMethods[0] != TransferMethodLocalFile {
+		t.Errorf("Unexpected file list %+v", files)
+	}
+	if params.SystemParameters.FileSizeLimit != 15 {
+		t.Errorf("Expected file size limit 15, got %d", params.SystemParameters.FileSizeLimit)
+	}
+
+	encoded, err := json.Marshal(question)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"text-input":{"label":"问题","variable":"question","required":true,"default":"","max_length":48}}`
+	if string(encoded) != want {
+		t.Errorf("Expected %s, got %s", want, encoded)
+	}
+}
+
+func TestAppMetaToolIcons(t *testing.T) {
+	var meta AppMeta
+	data := `{"tool_icons": {"dalle2": "https://example.com/icon.png", "api_tool": {"background": "#252525", "content": "😁"}}}`
+	if err := json.Unmarshal([]byte(data), &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.ToolIcons["dalle2"].URL != "https://example.com/icon.png" {
+		t.Errorf("Unexpected URL icon %+v", meta.ToolIcons["dalle2"])
+	}
+	if icon := meta.ToolIcons["api_tool"]; icon.Content != "😁" || icon.Background != "#252525" {
+		t.Errorf("Unexpected emoji icon %+v", icon)
+	}
+}
@@ -1,13 +1,9 @@
 package dify
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
-	"regexp"
 	"resty.dev/v3"
-	"strings"
 )
 
 // CreateChatApp 创建聊天应用
@@ -17,6 +13,7 @@ func (c *client) CreateChatApp(ctx context.Context, req *CreateChatAppRequest) (
 	var finalResponse *resty.Response
 
 	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
 		response, err := c.console().
 			WithContext(ctx).
 			SetContentType("application/json").
@@ -74,7 +71,27 @@ func (c *client) UpdateAppModelConfig(ctx context.Context, req *UpdateAppModelCo
 		})
 	}
 
+	metadataFilteringMode := req.MetadataFilteringMode
+	if req.MetadataFilteringConditions != nil {
+		if err := req.MetadataFilteringConditions.Validate(); err != nil {
+			return nil, err
+		}
+		if metadataFilteringMode == "" {
+			metadataFilteringMode = MetadataFilteringModeManual
+		}
+	}
+
+	speechToText := SpeechToTextConfig{Enabled: false}
+	if req.SpeechToText != nil {
+		speechToText = *req.SpeechToText
+	}
+	textToSpeech := TextToSpeechConfig{Enabled: false}
+	if req.TextToSpeech != nil {
+		textToSpeech = *req.TextToSpeech
+	}
+
 	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
 		response, err := c.console().
 			WithContext(ctx).
 			SetContentType("application/json").
@@ -89,8 +106,8 @@ func (c *client) UpdateAppModelConfig(ctx context.Context, req *UpdateAppModelCo
 				OpeningStatement:              "",
 				SuggestedQuestions:            []interface{}{},
 				SensitiveWordAvoidance:        SensitiveWordAvoidanceConfig{Enabled: false, Type: "", Configs: []interface{}{}},
-				SpeechToText:                  SpeechToTextConfig{Enabled: false},
-				TextToSpeech:                  TextToSpeechConfig{Enabled: false},
+				SpeechToText:                  speechToText,
+				TextToSpeech:                  textToSpeech,
 				FileUpload:                    getDefaultFileUploadConfig(),
 				SuggestedQuestionsAfterAnswer: SuggestedQuestionsAfterAnswerConfig{Enabled: false},
 				RetrieverResource:             RetrieverResourceConfig{Enabled: true},
@@ -103,6 +120,9 @@ func (c *client) UpdateAppModelConfig(ctx context.Context, req *UpdateAppModelCo
 					RerankingModel:  RerankingModelConfig{RerankingProviderName: "", RerankingModelName: ""},
 					RerankingEnable: false,
 					Datasets:        DatasetsWrapper{Datasets: datasets},
+
+					MetadataFilteringMode:       metadataFilteringMode,
+					MetadataFilteringConditions: req.MetadataFilteringConditions,
 				},
 			}).
 			SetResult(&resp).
@@ -141,6 +161,7 @@ func (c *client) CreateAppAccessToken(ctx context.Context, req *CreateAppAccessT
 	var finalResponse *resty.Response
 
 	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
 		response, err := c.console().
 			WithContext(ctx).
 			SetContentType("application/json").
@@ -190,104 +211,48 @@ func getDefaultFileUploadConfig() FileUploadConfig {
 	}
 }
 
-func (c *client) CallWorkflowAppBlocking(ctx context.Context, req *CallWorkflowRequest) (*Response[CallWorkflowCompletionResponse], error) {
-	var resultErr error
+// CallWorkflowAppBlocking 以阻塞模式执行工作流
+// 应用 API 密钥无效时返回 *AppAuthError
+func (a *appClient) CallWorkflowAppBlocking(ctx context.Context, req *CallWorkflowRequest) (*Response[CallWorkflowCompletionResponse], error) {
 	var resp = &CallWorkflowCompletionResponse{}
-	var finalResponse *resty.Response
-
 	req.ResponseMode = ResponseModeBlocking
-	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
-		response, err := c.console().
-			WithContext(ctx).
-			SetContentType("application/json").
-			SetBody(req).
-			SetHeader("Authorization", "Bearer "+req.Token).
-			SetResult(&CallWorkflowCompletionResponse{}).
-			Post(fmt.Sprintf("/v1/workflows/run"))
-
-		finalResponse = response
-
-		if err != nil {
-			return response, fmt.Errorf("failed to call workflow app: %w", err)
-		}
-
-		if response.IsError() {
-			resultErr = fmt.Errorf("failed to call workflow app with status %d: %s", response.StatusCode(), response.String())
-			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
-		}
-
-		resp = response.Result().(*CallWorkflowCompletionResponse)
-		return response, nil
-	})
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post(workflowRunPath(req))
 	if err != nil {
+		return nil, fmt.Errorf("failed to call workflow app: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
 		return nil, err
 	}
-	if resultErr != nil {
-		return nil, resultErr
+	if response.IsError() {
+		return nil, fmt.Errorf("failed to call workflow app with status %d: %s", response.StatusCode(), response.String())
 	}
-	return buildResponse[CallWorkflowCompletionResponse](finalResponse, resp), nil
+	return buildResponse[CallWorkflowCompletionResponse](response, resp), nil
 }
 
-func (c *client) CallWorkflowAppStreaming(ctx context.Context, req *CallWorkflowRequest) (chan *CallWorkflowChunkCompletionResponse, error) {
-	var resultErr error
+// CallWorkflowAppStreaming 以流式模式执行工作流
+// 应用 API 密钥无效时返回 *AppAuthError
+func (a *appClient) CallWorkflowAppStreaming(ctx context.Context, req *CallWorkflowRequest) (chan *CallWorkflowChunkCompletionResponse, error) {
 	req.ResponseMode = ResponseModeStreaming
-	resp, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
-		response, err := c.console().
-			SetDoNotParseResponse(true).
-			WithContext(ctx).
-			SetContentType("application/json").
-			SetBody(req).
-			SetHeader("Authorization", "Bearer "+req.Token).
-			Post(fmt.Sprintf("/v1/workflows/run"))
-
-		if err != nil {
-			return response, fmt.Errorf("failed to call workflow app: %w", err)
-		}
-		if response.IsError() {
-			resultErr = fmt.Errorf("failed to call workflow app with status %d: %s", response.StatusCode(), response.String())
-			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
-		}
-		return response, nil
-	})
+	response, err := a.request().
+		SetDoNotParseResponse(true).
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		Post(workflowRunPath(req))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to call workflow app: %w", err)
 	}
-	if resultErr != nil {
-		return nil, resultErr
+	if response.IsError() {
+		return nil, readAppStreamError(response, "call workflow app")
 	}
 
 	// 处理 SSE 流
-	chunks := make(chan *CallWorkflowChunkCompletionResponse)
-	go func() {
-		defer close(chunks)
-		defer resp.Body.Close()
-
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if matched, _ := regexp.MatchString(`^(data|event|id|retry):\s?.*`, line); matched {
-				sseLine, err := parseSSELine(line)
-				if err != nil {
-					continue
-				}
-				chunks <- sseLine
-			}
-		}
-	}()
-	return chunks, nil
-}
-
-func parseSSELine(line string) (*CallWorkflowChunkCompletionResponse, error) {
-	if strings.HasPrefix(line, "data: ") {
-		rawJSON := strings.TrimPrefix(line, "data: ")
-		var msg CallWorkflowChunkCompletionResponse
-		err := json.Unmarshal([]byte(rawJSON), &msg)
-		if err != nil {
-			return nil, err
-		}
-		return &msg, nil
-	}
-	return nil, fmt.Errorf("invalid line: does not start with 'data: '")
+	return streamEvents[CallWorkflowChunkCompletionResponse](response.Body), nil
 }
 
 func getDefaultAgentModeConfig() AgentModeConfig {
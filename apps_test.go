@@ -2,17 +2,17 @@ package dify
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestCreateChatApp(t *testing.T) {
 	// Create client
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
-	if err != nil {
-		t.Fatal(err)
-	}
+	c, _ := newTestClient(t)
 
 	ctx := context.Background()
 
@@ -57,10 +57,7 @@ func TestCreateChatApp(t *testing.T) {
 
 func TestCreateChatAppWithDataset(t *testing.T) {
 	// Create client
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
-	if err != nil {
-		t.Fatal(err)
-	}
+	c, _ := newTestClient(t)
 
 	ctx := context.Background()
 
@@ -130,10 +127,7 @@ func TestCreateChatAppWithDataset(t *testing.T) {
 
 func TestCreateAppAccessToken(t *testing.T) {
 	// Create client
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
-	if err != nil {
-		t.Fatal(err)
-	}
+	c, _ := newTestClient(t)
 
 	ctx := context.Background()
 
@@ -185,10 +179,7 @@ func TestCreateAppAccessToken(t *testing.T) {
 
 func TestCallWorkflowAppBlocking(t *testing.T) {
 	// Create client
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
-	if err != nil {
-		t.Fatal(err)
-	}
+	c, _ := newTestClient(t)
 
 	ctx := context.Background()
 
@@ -202,7 +193,7 @@ func TestCallWorkflowAppBlocking(t *testing.T) {
 		},
 		ResponseMode: ResponseModeBlocking,
 		User:         "test_user",
-		Token:        "your-token",
+		Token:        newTestAppToken(t, c),
 	})
 	if err != nil {
 		t.Fatal("Failed to call workflow app blocking:", err)
@@ -215,10 +206,7 @@ func TestCallWorkflowAppBlocking(t *testing.T) {
 
 func TestCallWorkflowAppStreaming(t *testing.T) {
 	// Create client
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
-	if err != nil {
-		t.Fatal(err)
-	}
+	c, _ := newTestClient(t)
 
 	ctx := context.Background()
 
@@ -232,7 +220,7 @@ func TestCallWorkflowAppStreaming(t *testing.T) {
 		},
 		ResponseMode: ResponseModeStreaming,
 		User:         "test_user",
-		Token:        "your-token",
+		Token:        newTestAppToken(t, c),
 	})
 	if err != nil {
 		t.Fatal("Failed to call workflow app blocking:", err)
@@ -245,3 +233,96 @@ func TestCallWorkflowAppStreaming(t *testing.T) {
 		t.Logf("Received chunk: %s", chunk.Data.Text)
 	}
 }
+
+func TestCallWorkflowAppStreamingSlowStream(t *testing.T) {
+	c, server := newTestClient(t)
+	token := newTestAppToken(t, c)
+	server.SetStreamDelay(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	resp, err := c.CallWorkflowAppStreaming(ctx, &CallWorkflowRequest{
+		Inputs:       map[string]interface{}{"question": "slow"},
+		ResponseMode: ResponseModeStreaming,
+		User:         "test_user",
+		Token:        token,
+	})
+	if err != nil {
+		t.Fatal("Failed to call workflow app streaming:", err)
+	}
+
+	var events []string
+	for chunk := range resp {
+		events = append(events, chunk.Event)
+	}
+	if len(events) == 0 {
+		t.Fatal("Expected events before the context deadline")
+	}
+	if events[len(events)-1] == WorkflowEventWorkflowFinished {
+		t.Fatalf("Expected the stream to be cut off by the context, got %v", events)
+	}
+}
+
+func TestCallWorkflowAppInvalidToken(t *testing.T) {
+	c, server := newTestClient(t)
+
+	_, err := c.CallWorkflowAppBlocking(context.Background(), &CallWorkflowRequest{
+		Inputs: map[string]interface{}{},
+		User:   "test_user",
+		Token:  "app-invalid",
+	})
+	var authErr *AppAuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("Expected *AppAuthError, got %v", err)
+	}
+	if authErr.Status != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", authErr.Status)
+	}
+
+	_, err = c.CallWorkflowAppStreaming(context.Background(), &CallWorkflowRequest{
+		Inputs: map[string]interface{}{},
+		User:   "test_user",
+		Token:  "app-invalid",
+	})
+	if !errors.As(err, &authErr) {
+		t.Fatalf("Expected *AppAuthError from streaming, got %v", err)
+	}
+
+	for _, r := range server.Requests() {
+		if r.Path == "/console/api/refresh-token" {
+			t.Error("A bad app token must not refresh the console token")
+		}
+		if strings.HasPrefix(r.Path, "/v1/workflows") && r.Authorization != "Bearer app-invalid" {
+			t.Errorf("Expected the app key on %s, got %q", r.Path, r.Authorization)
+		}
+	}
+}
+
+func TestCallWorkflowAppRetriesRateLimit(t *testing.T) {
+	c, server := newTestClient(t)
+	token := newTestAppToken(t, c)
+
+	server.FailNext(http.MethodPost, "/v1/workflows/run", http.StatusTooManyRequests)
+	resp, err := c.CallWorkflowAppBlocking(context.Background(), &CallWorkflowRequest{
+		Inputs: map[string]interface{}{},
+		User:   "test_user",
+		Token:  token,
+	})
+	if err != nil {
+		t.Fatal("Expected the rate limited run to be retried:", err)
+	}
+	if resp.Result.Data.Status != "succeeded" {
+		t.Errorf("Expected workflow to succeed, got %s", resp.Result.Data.Status)
+	}
+
+	// 500 may come after the workflow started, so POST is not retried
+	server.FailNext(http.MethodPost, "/v1/workflows/run", http.StatusInternalServerError)
+	if _, err := c.CallWorkflowAppBlocking(context.Background(), &CallWorkflowRequest{
+		Inputs: map[string]interface{}{},
+		User:   "test_user",
+		Token:  token,
+	}); err == nil {
+		t.Fatal("Expected 500 to be returned without retry")
+	}
+}
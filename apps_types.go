@@ -40,6 +40,12 @@ type UpdateAppModelConfigRequest struct {
 	AppID     string      `json:"-"`     // 应用ID，不包含在JSON中
 	Model     ModelConfig `json:"model"` // 模型配置
 	DatasetID string      `json:"-"`     // 数据集ID，不包含在JSON中
+	// 知识检索的元数据过滤模式，设置 MetadataFilteringConditions 且未指定模式时为 manual
+	MetadataFilteringMode       MetadataFilteringMode        `json:"-"`
+	MetadataFilteringConditions *MetadataFilteringConditions `json:"-"`
+	// 语音转文字、文字转语音配置，为 nil 时关闭
+	SpeechToText *SpeechToTextConfig `json:"-"`
+	TextToSpeech *TextToSpeechConfig `json:"-"`
 }
 
 // ModelConfig 模型配置
@@ -93,9 +99,20 @@ type SpeechToTextConfig struct {
 }
 
 type TextToSpeechConfig struct {
-	Enabled bool `json:"enabled"`
+	Enabled  bool        `json:"enabled"`
+	Voice    string      `json:"voice,omitempty"`    // 音色，可选值取决于 TTS 模型
+	Language string      `json:"language,omitempty"` // 语言，如 zh-Hans、en-US
+	AutoPlay TTSAutoPlay `json:"autoPlay,omitempty"` // WebApp 中是否自动播放
 }
 
+// TTSAutoPlay 文字转语音自动播放
+type TTSAutoPlay string
+
+const (
+	TTSAutoPlayEnabled  TTSAutoPlay = "enabled"
+	TTSAutoPlayDisabled TTSAutoPlay = "disabled"
+)
+
 type FileUploadConfig struct {
 	Image                    ImageUploadConfig `json:"image"`
 	Enabled                  bool              `json:"enabled"`
@@ -134,6 +151,9 @@ type DatasetConfigs struct {
 	RerankingModel  RerankingModelConfig `json:"reranking_model"`
 	RerankingEnable bool                 `json:"reranking_enable"`
 	Datasets        DatasetsWrapper      `json:"datasets"`
+
+	MetadataFilteringMode       MetadataFilteringMode        `json:"metadata_filtering_mode,omitempty"`
+	MetadataFilteringConditions *MetadataFilteringConditions `json:"metadata_filtering_conditions,omitempty"`
 }
 
 type RerankingModelConfig struct {
@@ -179,7 +199,9 @@ type CallWorkflowRequest struct {
 	Inputs       interface{}  `json:"inputs"` // 输入数据
 	ResponseMode ResponseMode `json:"response_mode"`
 	User         string       `json:"user"`
-	Token        string       `json:"token,omitempty"` // 访问令牌
+	Files        []InputFile  `json:"files,omitempty"` // 上传的文件，文件类型变量请直接放入 Inputs
+	Token        string       `json:"-"`               // 应用访问令牌，使用 AppClient 时无需设置
+	WorkflowID   string       `json:"-"`               // 指定运行的已发布工作流版本 ID，为空时运行最新发布版本
 }
 
 type CallWorkflowCompletionData struct {
@@ -208,6 +230,11 @@ type CallWorkflowChunkCompletionResponse struct {
 	Data          WorkflowData `json:"data"`
 }
 
+// EventType 以 WorkflowEvent 返回事件类型，便于与 WorkflowEvent 常量比较
+func (r *CallWorkflowChunkCompletionResponse) EventType() WorkflowEvent {
+	return WorkflowEvent(r.Event)
+}
+
 type WorkflowData struct {
 	ID             string                 `json:"id"`
 	WorkflowID     string                 `json:"workflow_id"`
@@ -215,4 +242,17 @@ type WorkflowData struct {
 	Status         string                 `json:"status"`
 	Outputs        map[string]interface{} `json:"outputs"` // 可根据需要更具体定义
 	Text           string                 `json:"text"`
+
+	// 节点事件（node_started / node_finished）
+	NodeID            string                 `json:"node_id"`
+	NodeType          NodeType               `json:"node_type"`
+	Title             string                 `json:"title"`
+	Index             int                    `json:"index"`
+	Inputs            map[string]interface{} `json:"inputs"`
+	ProcessData       map[string]interface{} `json:"process_data"`
+	Error             string                 `json:"error"`
+	ElapsedTime       float64                `json:"elapsed_time"`
+	ExecutionMetadata map[string]interface{} `json:"execution_metadata"`
+	CreatedAt         int64                  `json:"created_at"`
+	FinishedAt        int64                  `json:"finished_at"`
 }
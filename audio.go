@@ -0,0 +1,70 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// AudioToText 语音转文字
+// 支持 mp3、mp4、mpeg、mpga、m4a、wav、webm 格式，文件大小限制为 15MB
+func (a *appClient) AudioToText(ctx context.Context, req *AudioToTextRequest) (*Response[AudioToTextResponse], error) {
+	var resp = &AudioToTextResponse{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetRetryCount(0). // 文件内容只能读取一次，不能重试
+		SetFileReader("file", req.Filename, req.File).
+		SetFormData(map[string]string{
+			"user": req.User,
+		}).
+		SetResult(&resp).
+		Post("/audio-to-text")
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert audio to text: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[AudioToTextResponse](response, resp), nil
+}
+
+// TextToAudio 文字转语音，返回语音数据（通常为 mp3），调用方负责关闭
+// 同时设置 MessageID 和 Text 时优先使用 MessageID 对应的消息内容
+func (a *appClient) TextToAudio(ctx context.Context, req *TextToAudioRequest) (io.ReadCloser, error) {
+	if req.MessageID == "" && req.Text == "" {
+		return nil, fmt.Errorf("text to audio requires a message ID or text")
+	}
+	response, err := a.request().
+		SetDoNotParseResponse(true).
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		Post("/text-to-audio")
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert text to audio: %w", err)
+	}
+	if response.IsError() {
+		return nil, readAppStreamError(response, "convert text to audio")
+	}
+	return response.Body, nil
+}
+
+// AudioToTextRequest 语音转文字请求
+type AudioToTextRequest struct {
+	User     string    // 用户标识
+	Filename string    // 文件名，服务端根据扩展名判断格式
+	File     io.Reader // 语音内容
+}
+
+// AudioToTextResponse 语音转文字响应
+type AudioToTextResponse struct {
+	Text string `json:"text"`
+}
+
+// TextToAudioRequest 文字转语音请求
+type TextToAudioRequest struct {
+	MessageID string `json:"message_id,omitempty"` // 消息ID，合成该消息的回答
+	Text      string `json:"text,omitempty"`       // 需要合成的文本
+	User      string `json:"user"`                 // 用户标识
+	Voice     string `json:"voice,omitempty"`      // 音色，为空时使用应用配置的音色
+}
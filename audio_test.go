@@ -0,0 +1,117 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTextToAudio(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/text-to-audio" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.TrimSpace(string(body)) != `{"text":"你好","user":"u","voice":"alloy"}` {
+			t.Errorf("Unexpected body %s", body)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte("ID3-audio"))
+	}))
+	defer server.Close()
+
+	audio, err := NewAppClient(server.URL, "app-key").TextToAudio(context.Background(), &TextToAudioRequest{
+		Text:  "你好",
+		User:  "u",
+		Voice: "alloy",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer audio.Close()
+	data, _ := io.ReadAll(audio)
+	if string(data) != "ID3-audio" {
+		t.Errorf("Unexpected audio %q", data)
+	}
+
+	if _, err := NewAppClient(server.URL, "app-key").TextToAudio(context.Background(), &TextToAudioRequest{User: "u"}); err == nil {
+		t.Error("Expected an error without text or message ID")
+	}
+}
+
+func TestAudioToText(t *testing.T) {
+	c, server := newTestClient(t)
+	app := NewAppClient(server.URL, newTestAppToken(t, c))
+	ctx := context.Background()
+
+	resp, err := app.AudioToText(ctx, &AudioToTextRequest{User: "u", Filename: "hello.mp3", File: strings.NewReader("hello world")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsSuccess() || resp.Result.Text != "hello world" {
+		t.Fatalf("Unexpected transcript %d %+v", resp.StatusCode(), resp.Result)
+	}
+
+	// 缺少 user 字段或格式不受支持时由服务端拒绝
+	resp, err = app.AudioToText(ctx, &AudioToTextRequest{Filename: "hello.mp3", File: strings.NewReader("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusBadRequest {
+		t.Errorf("Expected 400 without user, got %d", resp.StatusCode())
+	}
+	resp, err = app.AudioToText(ctx, &AudioToTextRequest{User: "u", Filename: "hello.txt", File: strings.NewReader("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusUnsupportedMediaType || resp.Code != "unsupported_audio_type" {
+		t.Errorf("Expected 415 unsupported_audio_type, got %d %s", resp.StatusCode(), resp.Code)
+	}
+
+	// 文件内容只能读取一次，503 也不重试
+	server.FailNext(http.MethodPost, "/v1/audio-to-text", http.StatusServiceUnavailable)
+	before := len(server.Requests())
+	resp, err = app.AudioToText(ctx, &AudioToTextRequest{User: "u", Filename: "hello.mp3", File: strings.NewReader("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", resp.StatusCode())
+	}
+	if n := len(server.Requests()) - before; n != 1 {
+		t.Errorf("Expected a single request without retries, got %d", n)
+	}
+}
+
+func TestChatStreamTTSMessage(t *testing.T) {
+	stream := "data: {\"event\":\"tts_message\",\"message_id\":\"m\",\"audio\":\"SUQzAQ==\"}\n\n" +
+		"data: {\"event\":\"tts_message_end\",\"message_id\":\"m\",\"audio\":\"\"}\n\n"
+	var chunks []*ChatMessageChunk
+	for chunk := range streamEvents[ChatMessageChunk](io.NopCloser(strings.NewReader(stream))) {
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Event != MessageEventTTSMessage || string(chunks[0].Audio) != "ID3\x01" {
+		t.Errorf("Unexpected tts chunk %+v", chunks[0])
+	}
+	if chunks[1].Event != MessageEventTTSMessageEnd || len(chunks[1].Audio) != 0 {
+		t.Errorf("Unexpected tts end chunk %+v", chunks[1])
+	}
+}
+
+func TestTextToSpeechConfig(t *testing.T) {
+	data, err := json.Marshal(TextToSpeechConfig{Enabled: true, Voice: "alloy", Language: "zh-Hans", AutoPlay: TTSAutoPlayEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"enabled":true,"voice":"alloy","language":"zh-Hans","autoPlay":"enabled"}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
@@ -0,0 +1,129 @@
+package dify
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fzdwx/dify/difytest"
+)
+
+// newCassetteClient 创建通过磁带服务访问 Dify 的客户端
+// 默认回放 testdata/cassettes 下的磁带；设置 DIFY_CASSETTE=record、DIFY_URL、DIFY_EMAIL、DIFY_PASSWORD 后重新录制。
+// 磁带必须录制自真实的 Dify，不要对 difytest 录制；线上格式变化时重新录制，不要手工修改磁带。
+// 磁带尚未录制时跳过测试
+func newCassetteClient(t *testing.T, name string) Client {
+	t.Helper()
+	path := filepath.Join("testdata", "cassettes", name+".json")
+	if difytest.CassetteMode(os.Getenv("DIFY_CASSETTE")) != difytest.CassetteModeRecord {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			t.Skipf("%s has not been recorded; record it against a real Dify with DIFY_CASSETTE=record", path)
+		}
+	}
+	server, err := difytest.NewCassetteServerFromEnv(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if server.Mode() == difytest.CassetteModeReplay {
+			for _, interaction := range server.Unused() {
+				t.Errorf("Recorded request was not replayed: %s %s", interaction.Request.Method, interaction.Request.Path)
+			}
+		}
+		if err := server.Close(); err != nil {
+			t.Error("Failed to save cassette:", err)
+		}
+	})
+
+	email, password := difytest.DefaultEmail, difytest.DefaultPassword
+	if server.Mode() == difytest.CassetteModeRecord {
+		email, password = os.Getenv("DIFY_EMAIL"), os.Getenv("DIFY_PASSWORD")
+	}
+	c, err := NewClient(server.URL, email, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCallWorkflowAppStreamingCassette(t *testing.T) {
+	c := newCassetteClient(t, "call_workflow_app_streaming")
+
+	// 录制时需要真实的工作流应用 API 密钥，回放时密钥已被脱敏
+	token := os.Getenv("DIFY_WORKFLOW_TOKEN")
+	if token == "" {
+		token = "app-[REDACTED]"
+	}
+	resp, err := c.CallWorkflowAppStreaming(context.Background(), &CallWorkflowRequest{
+		Inputs:       map[string]interface{}{"question": "设备运行状态分类汇总统计"},
+		ResponseMode: ResponseModeStreaming,
+		User:         "test_user",
+		Token:        token,
+	})
+	if err != nil {
+		t.Fatal("Failed to call workflow app streaming:", err)
+	}
+
+	var events []string
+	var finished *CallWorkflowChunkCompletionResponse
+	for chunk := range resp {
+		events = append(events, chunk.Event)
+		if chunk.Event == WorkflowEventWorkflowFinished {
+			finished = chunk
+		}
+	}
+	if len(events) == 0 || events[0] != WorkflowEventWorkflowStarted {
+		t.Fatalf("Expected the stream to start with workflow_started, got %v", events)
+	}
+	if finished == nil {
+		t.Fatalf("Expected a workflow_finished event, got %v", events)
+	}
+	if finished.Data.Status != "succeeded" {
+		t.Errorf("Expected workflow to succeed, got %s", finished.Data.Status)
+	}
+}
+
+func TestCreateByFileCassette(t *testing.T) {
+	c := newCassetteClient(t, "create_by_file")
+	ctx := context.Background()
+
+	datasetResp, err := c.CreateEmptyDataset(ctx, &CreateEmptyDatasetRequest{
+		Name:              "cassette_create_by_file",
+		IndexingTechnique: IndexingTechniqueEconomy,
+		Permission:        DatasetPermissionOnlyMe,
+		Provider:          DatasetProviderVendor,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !datasetResp.IsSuccess() {
+		t.Fatal(datasetResp.Message)
+	}
+
+	file, err := os.Open("./testdata/aaaa.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	resp, err := c.CreateByFile(ctx, &CreateByFileRequest{
+		DatasetsID:        datasetResp.Result.ID,
+		Filename:          "aaaa.docx",
+		FileBody:          file,
+		IndexingTechnique: IndexingTechniqueEconomy,
+		DocForm:           DocFormTextModel,
+		ProcessRule:       ProcessRule{Mode: ProcessModeAutomatic},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal(resp.Message)
+	}
+	if resp.Result.Document.Name != "aaaa.docx" || resp.Result.Batch == "" {
+		t.Errorf("Unexpected document %+v (batch %q)", resp.Result.Document, resp.Result.Batch)
+	}
+}
@@ -0,0 +1,94 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+)
+
+// SendChatMessage 发送对话消息，返回阻塞响应
+// 适用于 chat / advanced-chat / agent-chat 类型的应用
+func (a *appClient) SendChatMessage(ctx context.Context, req *SendChatMessageRequest) (*Response[ChatMessageResponse], error) {
+	var resp = &ChatMessageResponse{}
+	req.ResponseMode = ResponseModeBlocking
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post("/chat-messages")
+	if err != nil {
+		return nil, fmt.Errorf("failed to send chat message: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ChatMessageResponse](response, resp), nil
+}
+
+// SendChatMessageStreaming 发送对话消息，返回 SSE 流
+func (a *appClient) SendChatMessageStreaming(ctx context.Context, req *SendChatMessageRequest) (chan *ChatMessageChunk, error) {
+	req.ResponseMode = ResponseModeStreaming
+	response, err := a.request().
+		SetDoNotParseResponse(true).
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		Post("/chat-messages")
+	if err != nil {
+		return nil, fmt.Errorf("failed to send chat message: %w", err)
+	}
+	if response.IsError() {
+		return nil, readAppStreamError(response, "send chat message")
+	}
+	return streamEvents[ChatMessageChunk](response.Body), nil
+}
+
+// SendChatMessageRequest 发送对话消息请求
+type SendChatMessageRequest struct {
+	Query            string                 `json:"query"`  // 用户输入/提问内容
+	Inputs           map[string]interface{} `json:"inputs"` // 应用定义的变量值
+	ResponseMode     ResponseMode           `json:"response_mode"`
+	User             string                 `json:"user"`                      // 用户标识
+	ConversationID   string                 `json:"conversation_id,omitempty"` // 会话 ID，为空时开启新会话
+	Files            []InputFile            `json:"files,omitempty"`           // 上传的文件
+	AutoGenerateName *bool                  `json:"auto_generate_name,omitempty"`
+	Token            string                 `json:"-"` // 应用访问令牌
+}
+
+// ChatMessageResponse 对话消息阻塞响应
+type ChatMessageResponse struct {
+	Event          string          `json:"event"`
+	TaskID         string          `json:"task_id"`
+	ID             string          `json:"id"`
+	MessageID      string          `json:"message_id"`
+	ConversationID string          `json:"conversation_id"`
+	Mode           string          `json:"mode"`
+	Answer         string          `json:"answer"`
+	Metadata       MessageMetadata `json:"metadata"`
+	CreatedAt      int64           `json:"created_at"`
+}
+
+// ChatMessageChunk 对话消息流式响应中的一个事件
+type ChatMessageChunk struct {
+	Event          MessageEvent    `json:"event"`
+	TaskID         string          `json:"task_id"`
+	ID             string          `json:"id"`
+	MessageID      string          `json:"message_id"`
+	ConversationID string          `json:"conversation_id"`
+	Answer         string          `json:"answer"`   // message / agent_message / message_replace 事件的文本内容
+	Metadata       MessageMetadata `json:"metadata"` // message_end 事件的元数据
+	CreatedAt      int64           `json:"created_at"`
+
+	// tts_message 事件，Base64 解码后的语音数据（mp3）
+	Audio []byte `json:"audio"`
+
+	// message_file 事件
+	Type      string `json:"type"`
+	BelongsTo string `json:"belongs_to"`
+	URL       string `json:"url"`
+
+	// error 事件
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
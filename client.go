@@ -4,17 +4,117 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"resty.dev/v3"
+	"sync"
+	"time"
 )
 
 type Client interface {
 	// Datasets
 
-	// CreateEmptyDataset 创建空知识库
+	// CreateEmptyDataset 创建空知识库，绑定标签失败时同时返回创建响应和错误
 	CreateEmptyDataset(ctx context.Context, req *CreateEmptyDatasetRequest) (*Response[CreateEmptyDatasetResponse], error)
 	// CreateByFile 通过文件创建文档
 	// 此接口基于已存在知识库，在此知识库的基础上通过文件创建新的文档
+	// 文件以流式上传，超过服务端上传限制时返回 ErrFileTooLarge
 	CreateByFile(ctx context.Context, req *CreateByFileRequest) (*Response[CreateByFileResponse], error)
+	// GetUploadConfig 获取服务端的文件上传限制
+	GetUploadConfig(ctx context.Context) (*Response[UploadConfig], error)
+	// IndexingEstimate 预估文档的分段结果和 embedding 消耗，不会创建文档
+	IndexingEstimate(ctx context.Context, req *IndexingEstimateRequest) (*Response[IndexingEstimateResponse], error)
+	// CompareProcessRules 使用多组 ProcessRule 预估同一文档的分段结果
+	CompareProcessRules(ctx context.Context, req *IndexingEstimateRequest, rules []ProcessRule) ([]*IndexingEstimateResponse, error)
+	// ListNotionPages 获取已授权的 Notion 工作空间及页面
+	ListNotionPages(ctx context.Context) (*Response[ListNotionPagesResponse], error)
+	// CreateByNotion 通过 Notion 页面创建文档
+	CreateByNotion(ctx context.Context, req *CreateByNotionRequest) (*Response[CreateDocumentsResponse], error)
+	// StartWebsiteCrawl 开始抓取网站
+	StartWebsiteCrawl(ctx context.Context, req *StartWebsiteCrawlRequest) (*Response[WebsiteCrawlJob], error)
+	// GetWebsiteCrawlStatus 获取网站抓取任务状态
+	GetWebsiteCrawlStatus(ctx context.Context, req *GetWebsiteCrawlStatusRequest) (*Response[WebsiteCrawlJob], error)
+	// WaitWebsiteCrawl 轮询网站抓取任务直到完成
+	WaitWebsiteCrawl(ctx context.Context, req *GetWebsiteCrawlStatusRequest, interval time.Duration) (*WebsiteCrawlJob, error)
+	// CreateByWebsite 通过网站抓取结果创建文档
+	CreateByWebsite(ctx context.Context, req *CreateByWebsiteRequest) (*Response[CreateDocumentsResponse], error)
+	// Retrieve 检索知识库
+	Retrieve(ctx context.Context, req *RetrieveRequest) (*Response[RetrieveResponse], error)
+	// HitTesting 召回测试，查询会记录到召回测试历史中
+	HitTesting(ctx context.Context, req *RetrieveRequest) (*Response[RetrieveResponse], error)
+	// ListHitTestingQueries 分页获取召回测试历史
+	ListHitTestingQueries(ctx context.Context, req *ListHitTestingQueriesRequest) (*Response[ListHitTestingQueriesResponse], error)
+	// CompareRetrievalModels 使用两套检索配置对同一组查询进行召回测试并对比
+	CompareRetrievalModels(ctx context.Context, req *CompareRetrievalModelsRequest) ([]RetrievalComparison, error)
+	// ListDatasets 分页获取知识库列表，支持按标签过滤
+	ListDatasets(ctx context.Context, req *ListDatasetsRequest) (*Response[ListDatasetsResponse], error)
+
+	// Documents
+
+	// GetIndexingStatus 获取一批文档的嵌入状态
+	GetIndexingStatus(ctx context.Context, req *GetIndexingStatusRequest) (*Response[IndexingStatusResponse], error)
+	// WaitIndexing 轮询一批文档的嵌入状态，直到全部文档完成、出错或暂停
+	WaitIndexing(ctx context.Context, req *GetIndexingStatusRequest, interval time.Duration) ([]DocumentIndexingStatus, error)
+	// ListDocuments 分页获取知识库中的文档，Keyword 按名称模糊搜索
+	ListDocuments(ctx context.Context, req *ListDocumentsRequest) (*Response[ListDocumentsResponse], error)
+	// DeleteDocument 删除文档
+	DeleteDocument(ctx context.Context, req *DeleteDocumentRequest) (*Response[ResultResponse], error)
+	// UpdateByFile 通过文件更新文档，更新后会重新分段和嵌入
+	UpdateByFile(ctx context.Context, req *UpdateByFileRequest) (*Response[CreateByFileResponse], error)
+	// UpdateDocumentsStatus 批量启用、禁用、归档或取消归档文档
+	UpdateDocumentsStatus(ctx context.Context, req *UpdateDocumentsStatusRequest) (*Response[ResultResponse], error)
+	// IngestFiles 把目录中匹配的文件批量上传到知识库，返回每个文件的结果
+	IngestFiles(ctx context.Context, req *IngestRequest) (*IngestReport, error)
+	// SyncDocuments 根据清单把目录同步到知识库：新增、更新变化的文件，删除或归档来源已不存在的文档
+	SyncDocuments(ctx context.Context, req *SyncRequest) (*SyncReport, error)
+
+	// Tags
+
+	// ListKnowledgeTags 获取知识库类型的标签列表
+	ListKnowledgeTags(ctx context.Context) (*Response[[]Tag], error)
+	// CreateKnowledgeTag 新增知识库类型的标签
+	CreateKnowledgeTag(ctx context.Context, req *CreateKnowledgeTagRequest) (*Response[Tag], error)
+	// UpdateKnowledgeTag 修改标签名称
+	UpdateKnowledgeTag(ctx context.Context, req *UpdateKnowledgeTagRequest) (*Response[Tag], error)
+	// DeleteKnowledgeTag 删除标签
+	DeleteKnowledgeTag(ctx context.Context, req *DeleteKnowledgeTagRequest) (*Response[ResultResponse], error)
+	// BindDatasetTags 为知识库绑定标签
+	BindDatasetTags(ctx context.Context, req *BindDatasetTagsRequest) (*Response[ResultResponse], error)
+	// UnbindDatasetTag 解除知识库的标签
+	UnbindDatasetTag(ctx context.Context, req *UnbindDatasetTagRequest) (*Response[ResultResponse], error)
+	// GetDatasetTags 获取知识库已绑定的标签
+	GetDatasetTags(ctx context.Context, req *GetDatasetTagsRequest) (*Response[GetDatasetTagsResponse], error)
+
+	// Metadata
+
+	// CreateMetadataField 新增知识库元数据字段
+	CreateMetadataField(ctx context.Context, req *CreateMetadataFieldRequest) (*Response[MetadataField], error)
+	// UpdateMetadataField 重命名知识库元数据字段
+	UpdateMetadataField(ctx context.Context, req *UpdateMetadataFieldRequest) (*Response[MetadataField], error)
+	// DeleteMetadataField 删除知识库元数据字段
+	DeleteMetadataField(ctx context.Context, req *DeleteMetadataFieldRequest) (*Response[ResultResponse], error)
+	// ListMetadataFields 获取知识库的元数据字段列表
+	ListMetadataFields(ctx context.Context, req *ListMetadataFieldsRequest) (*Response[ListMetadataFieldsResponse], error)
+	// ToggleBuiltInMetadataFields 启用或禁用内置元数据字段
+	ToggleBuiltInMetadataFields(ctx context.Context, req *ToggleBuiltInMetadataFieldsRequest) (*Response[ResultResponse], error)
+	// UpdateDocumentsMetadata 批量设置文档的元数据值
+	UpdateDocumentsMetadata(ctx context.Context, req *UpdateDocumentsMetadataRequest) (*Response[ResultResponse], error)
+
+	// External knowledge
+
+	// ListExternalKnowledgeAPIs 分页获取已注册的外部知识库 API
+	ListExternalKnowledgeAPIs(ctx context.Context, req *ListExternalKnowledgeAPIsRequest) (*Response[ListExternalKnowledgeAPIsResponse], error)
+	// CreateExternalKnowledgeAPI 注册外部知识库 API
+	CreateExternalKnowledgeAPI(ctx context.Context, req *CreateExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPI], error)
+	// GetExternalKnowledgeAPI 获取外部知识库 API 详情
+	GetExternalKnowledgeAPI(ctx context.Context, req *GetExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPI], error)
+	// UpdateExternalKnowledgeAPI 修改外部知识库 API
+	UpdateExternalKnowledgeAPI(ctx context.Context, req *UpdateExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPI], error)
+	// DeleteExternalKnowledgeAPI 删除外部知识库 API
+	DeleteExternalKnowledgeAPI(ctx context.Context, req *DeleteExternalKnowledgeAPIRequest) (*Response[ResultResponse], error)
+	// CheckExternalKnowledgeAPIUsage 检查外部知识库 API 是否被知识库引用
+	CheckExternalKnowledgeAPIUsage(ctx context.Context, req *GetExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPIUsage], error)
+	// CreateExternalDataset 创建关联外部知识库的知识库
+	CreateExternalDataset(ctx context.Context, req *CreateExternalDatasetRequest) (*Response[CreateEmptyDatasetResponse], error)
 
 	// Apps
 
@@ -25,14 +125,74 @@ type Client interface {
 	// CreateAppAccessToken 创建应用访问令牌
 	CreateAppAccessToken(ctx context.Context, req *CreateAppAccessTokenRequest) (*Response[CreateAppAccessTokenResponse], error)
 
+	// ExportAppDSL 导出应用 DSL（YAML）
+	ExportAppDSL(ctx context.Context, req *ExportAppDSLRequest) (*Response[ExportAppDSLResponse], error)
+	// ImportAppDSL 导入应用 DSL，创建新应用或覆盖已有应用
+	ImportAppDSL(ctx context.Context, req *ImportAppDSLRequest) (*Response[ImportAppDSLResponse], error)
+	// ConfirmAppDSLImport 确认 DSL 版本不一致时处于 pending 状态的导入
+	ConfirmAppDSLImport(ctx context.Context, req *ConfirmAppDSLImportRequest) (*Response[ImportAppDSLResponse], error)
+	// DiffAppDSL 比较线上应用 DSL 与本地 DSL 内容
+	DiffAppDSL(ctx context.Context, appID string, localYAML string) ([]DSLDiffLine, error)
+
+	// GetDraftWorkflow 获取应用的工作流草稿
+	GetDraftWorkflow(ctx context.Context, req *GetDraftWorkflowRequest) (*Response[DraftWorkflow], error)
+	// SaveDraftWorkflow 保存应用的工作流草稿（基于 hash 的乐观并发控制）
+	SaveDraftWorkflow(ctx context.Context, req *SaveDraftWorkflowRequest) (*Response[SaveDraftWorkflowResponse], error)
+	// PublishWorkflow 发布应用的工作流草稿
+	PublishWorkflow(ctx context.Context, req *PublishWorkflowRequest) (*Response[PublishWorkflowResponse], error)
+
+	// RunDraftWorkflow 调试运行工作流草稿，返回 SSE 流
+	RunDraftWorkflow(ctx context.Context, req *RunDraftWorkflowRequest) (chan *CallWorkflowChunkCompletionResponse, error)
+	// RunDraftWorkflowNode 调试运行工作流草稿中的单个节点，返回节点事件流
+	RunDraftWorkflowNode(ctx context.Context, req *RunDraftWorkflowNodeRequest) (chan *CallWorkflowChunkCompletionResponse, error)
+
 	// RefreshDatasetAPIKey 刷新 datasets API key（当 console token 过期时可能需要）
 	RefreshDatasetAPIKey() error
 
+	// App 返回绑定应用 API 密钥的运行时客户端，之后的调用无需在请求中传入 Token
+	App(appKey string) AppClient
+
 	// CallWorkflowAppBlocking 调用工作流应用，返回阻塞响应
 	CallWorkflowAppBlocking(ctx context.Context, req *CallWorkflowRequest) (*Response[CallWorkflowCompletionResponse], error)
 
 	// CallWorkflowAppStreaming 调用工作流应用，返回 SSE 流
 	CallWorkflowAppStreaming(ctx context.Context, req *CallWorkflowRequest) (chan *CallWorkflowChunkCompletionResponse, error)
+
+	// GetWorkflowRun 获取工作流执行情况
+	GetWorkflowRun(ctx context.Context, req *GetWorkflowRunRequest) (*Response[WorkflowRun], error)
+	// ListWorkflowLogs 分页获取工作流日志
+	ListWorkflowLogs(ctx context.Context, req *ListWorkflowLogsRequest) (*Response[ListWorkflowLogsResponse], error)
+
+	// ListPublishedWorkflows 分页获取应用已发布的工作流版本
+	ListPublishedWorkflows(ctx context.Context, req *ListPublishedWorkflowsRequest) (*Response[ListPublishedWorkflowsResponse], error)
+
+	// Completion
+
+	// SendCompletionMessage 发送文本生成消息，返回阻塞响应
+	SendCompletionMessage(ctx context.Context, req *SendCompletionMessageRequest) (*Response[CompletionMessageResponse], error)
+	// SendCompletionMessageStreaming 发送文本生成消息，返回 SSE 流
+	SendCompletionMessageStreaming(ctx context.Context, req *SendCompletionMessageRequest) (chan *CompletionMessageChunk, error)
+
+	// Chat
+
+	// SendChatMessage 发送对话消息，返回阻塞响应
+	SendChatMessage(ctx context.Context, req *SendChatMessageRequest) (*Response[ChatMessageResponse], error)
+	// SendChatMessageStreaming 发送对话消息，返回 SSE 流
+	SendChatMessageStreaming(ctx context.Context, req *SendChatMessageRequest) (chan *ChatMessageChunk, error)
+
+	// Files
+
+	// UploadFile 上传文件，用于对话消息或工作流的文件输入
+	UploadFile(ctx context.Context, req *UploadFileRequest) (*Response[UploadFileResponse], error)
+
+	// Messages
+
+	// SendMessageFeedback 消息反馈（点赞/点踩/撤销）
+	SendMessageFeedback(ctx context.Context, req *SendMessageFeedbackRequest) (*Response[ResultResponse], error)
+	// GetSuggestedQuestions 获取下一轮建议问题列表
+	GetSuggestedQuestions(ctx context.Context, req *GetSuggestedQuestionsRequest) (*Response[GetSuggestedQuestionsResponse], error)
+	// ListAppFeedbacks 分页获取应用的消息反馈列表
+	ListAppFeedbacks(ctx context.Context, req *ListAppFeedbacksRequest) (*Response[ListAppFeedbacksResponse], error)
 }
 
 func NewClient(baseUrl, email, password string) (Client, error) {
@@ -82,6 +242,7 @@ func NewClient(baseUrl, email, password string) (Client, error) {
 	return &client{
 		datasetsClient: datasetsClient,
 		consoleClient:  consoleClient,
+		appClient:      newAppClient(baseUrl),
 		datasetAPIKey:  datasetAPIKey,
 		refreshToken:   loginResp.Data.RefreshToken,
 		baseUrl:        baseUrl,
@@ -99,9 +260,15 @@ type Response[T any] struct {
 type client struct {
 	datasetsClient *resty.Client
 	consoleClient  *resty.Client // 用于 console API 调用
+	appClient      *resty.Client // 用于应用 API 调用，不携带 console token
 	datasetAPIKey  string        // datasets API key
 	refreshToken   string        // refresh token for console API
 	baseUrl        string        // base URL for API calls
+
+	uploadLimitMu       sync.Mutex
+	uploadLimit         int64     // 服务端的文档大小限制（字节），首次上传时获取
+	uploadLimitFetching bool      // 是否正在获取上传限制
+	uploadLimitFailedAt time.Time // 上次获取失败的时间，一段时间内不再重试
 }
 
 // datasets returns a request for datasets API (/v1/datasets/)
@@ -116,6 +283,37 @@ func (c *client) console() *resty.Request {
 	return c.consoleClient.R()
 }
 
+// newAppClient creates the client for app API (/v1/)
+// These APIs never see the console access token and do NOT refresh it on 401
+// 429 and 503 mean the request was not processed, so they are retried for every method;
+// other 5xx and network errors are only retried for GET, so a workflow run or message is never sent twice
+func newAppClient(baseUrl string) *resty.Client {
+	return resty.New().
+		SetBaseURL(baseUrl + "/v1").
+		SetRetryCount(2).
+		SetRetryWaitTime(500 * time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Second).
+		SetAllowNonIdempotentRetry(true).
+		SetRetryDefaultConditions(false).
+		AddRetryConditions(shouldRetryAppRequest)
+}
+
+func shouldRetryAppRequest(response *resty.Response, err error) bool {
+	if response == nil || response.Request == nil || response.Request.Context().Err() != nil {
+		return false
+	}
+	if err == nil {
+		switch response.StatusCode() {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		}
+	}
+	if response.Request.Method != http.MethodGet {
+		return false
+	}
+	return err != nil || (response.StatusCode() >= 500 && response.StatusCode() != http.StatusNotImplemented)
+}
+
 // RefreshDatasetAPIKey implements the Client interface
 func (c *client) RefreshDatasetAPIKey() error {
 	// Get or create a new dataset API key with retry
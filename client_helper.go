@@ -3,6 +3,7 @@ package dify
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"resty.dev/v3"
 )
 
@@ -53,6 +54,7 @@ func (c *client) getOrCreateDatasetAPIKeyWithRetry() (string, error) {
 	var resultErr error
 
 	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
 		// First, try to get existing API keys
 		var keysResp DatasetAPIKeysResponse
 		resp, err := c.consoleClient.R().
@@ -144,6 +146,7 @@ func (c *client) refreshAccessToken() error {
 
 // executeConsoleWithRetry executes a console API request with automatic token refresh on 401 errors
 // This should only be used for /console/api/ endpoints
+// requestFunc may run twice, so it must reset any state it records before each attempt
 func (c *client) executeConsoleWithRetry(requestFunc func() (*resty.Response, error)) (*resty.Response, error) {
 	// First attempt
 	response, err := requestFunc()
@@ -191,6 +194,17 @@ func buildResponse[T any](response *resty.Response, val *T) *Response[T] {
 	return resp
 }
 
+// errorCode 从错误响应中解析 Dify 返回的错误码
+func errorCode(response *resty.Response) string {
+	var errResp struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(response.Bytes(), &errResp); err != nil {
+		return ""
+	}
+	return errResp.Code
+}
+
 // LoginRequest represents the login request payload
 type LoginRequest struct {
 	Email      string `json:"email"`
@@ -235,3 +249,41 @@ type RefreshTokenResponse struct {
 		RefreshToken string `json:"refresh_token"`
 	} `json:"data"`
 }
+
+// ResultResponse 通用的操作结果响应，例如 {"result": "success"}
+type ResultResponse struct {
+	Result string `json:"result"`
+}
+
+// AppAuthError 应用 API 密钥无效、已删除或应用已停用
+type AppAuthError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *AppAuthError) Error() string {
+	return fmt.Sprintf("app API key rejected with status %d: %s (%s)", e.Status, e.Message, e.Code)
+}
+
+// newAppAuthError 根据错误响应体创建 AppAuthError
+func newAppAuthError(status int, body []byte) *AppAuthError {
+	authErr := &AppAuthError{Status: status}
+	var errResp struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		authErr.Code = errResp.Code
+		authErr.Message = errResp.Message
+	}
+	return authErr
+}
+
+// checkAppAuth 应用 API 返回 401 时返回 AppAuthError
+func checkAppAuth(response *resty.Response) error {
+	if response.StatusCode() == http.StatusUnauthorized {
+		return newAppAuthError(response.StatusCode(), response.Bytes())
+	}
+	return nil
+}
@@ -3,16 +3,59 @@ package dify
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"testing"
 	"time"
+
+	"github.com/fzdwx/dify/difytest"
 )
 
-func TestDatasets(t *testing.T) {
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
+// newTestClient 启动模拟服务并创建已登录的客户端
+func newTestClient(t *testing.T, opts ...difytest.Option) (Client, *difytest.Server) {
+	t.Helper()
+	server := difytest.NewServer(opts...)
+	t.Cleanup(server.Close)
+	c, err := NewClient(server.URL, difytest.DefaultEmail, difytest.DefaultPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, server
+}
+
+// newTestAppToken 在模拟服务中创建应用并返回应用的 API 密钥
+func newTestAppToken(t *testing.T, c Client) string {
+	t.Helper()
+	ctx := context.Background()
+	appResp, err := c.CreateChatApp(ctx, &CreateChatAppRequest{Name: "workflow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenResp, err := c.CreateAppAccessToken(ctx, &CreateAppAccessTokenRequest{AppID: appResp.Result.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tokenResp.IsSuccess() {
+		t.Fatal(tokenResp.Message)
+	}
+	return tokenResp.Result.Token
+}
+
+// newTestDataset 在模拟服务中创建空知识库并返回ID
+func newTestDataset(t *testing.T, c Client) string {
+	t.Helper()
+	resp, err := c.CreateEmptyDataset(context.Background(), &CreateEmptyDatasetRequest{Name: t.Name()})
 	if err != nil {
 		t.Fatal(err)
 	}
+	if !resp.IsSuccess() {
+		t.Fatal(resp.Message)
+	}
+	return resp.Result.ID
+}
+
+func TestDatasets(t *testing.T) {
+	c, _ := newTestClient(t)
 	ctx := context.Background()
 	// Use timestamp to create unique dataset name
 	uniqueName := fmt.Sprintf("测试数据集_%d", time.Now().Unix())
@@ -73,3 +116,42 @@ func TestDatasets(t *testing.T) {
 	}
 	t.Log(createFileResp)
 }
+
+func TestInjectedFailures(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+
+	req := &CreateEmptyDatasetRequest{
+		Name:              "failures",
+		IndexingTechnique: IndexingTechniqueEconomy,
+		Permission:        DatasetPermissionOnlyMe,
+		Provider:          DatasetProviderVendor,
+	}
+
+	server.FailNext(http.MethodPost, "/v1/datasets", http.StatusTooManyRequests)
+	resp, err := c.CreateEmptyDataset(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.IsSuccess() || resp.StatusCode() != http.StatusTooManyRequests || resp.Code != "too_many_requests" {
+		t.Fatalf("Expected 429 too_many_requests, got %d %s", resp.StatusCode(), resp.Code)
+	}
+
+	server.Fail(difytest.Failure{Path: "/v1/*", Status: http.StatusInternalServerError, Code: "internal_server_error", Times: 1})
+	resp, err = c.CreateEmptyDataset(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusInternalServerError {
+		t.Fatalf("Expected 500, got %d", resp.StatusCode())
+	}
+
+	// Failures are used up, the request goes through now
+	resp, err = c.CreateEmptyDataset(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal(resp.Message)
+	}
+}
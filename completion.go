@@ -0,0 +1,129 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+)
+
+// SendCompletionMessage 发送文本生成消息，返回阻塞响应
+// 仅适用于 completion（文本生成）类型的应用
+func (a *appClient) SendCompletionMessage(ctx context.Context, req *SendCompletionMessageRequest) (*Response[CompletionMessageResponse], error) {
+	var resp = &CompletionMessageResponse{}
+	req.ResponseMode = ResponseModeBlocking
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post("/completion-messages")
+	if err != nil {
+		return nil, fmt.Errorf("failed to send completion message: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[CompletionMessageResponse](response, resp), nil
+}
+
+// SendCompletionMessageStreaming 发送文本生成消息，返回 SSE 流
+func (a *appClient) SendCompletionMessageStreaming(ctx context.Context, req *SendCompletionMessageRequest) (chan *CompletionMessageChunk, error) {
+	req.ResponseMode = ResponseModeStreaming
+	response, err := a.request().
+		SetDoNotParseResponse(true).
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		Post("/completion-messages")
+	if err != nil {
+		return nil, fmt.Errorf("failed to send completion message: %w", err)
+	}
+	if response.IsError() {
+		return nil, readAppStreamError(response, "send completion message")
+	}
+	return streamEvents[CompletionMessageChunk](response.Body), nil
+}
+
+// MessageEvent 文本生成 / 对话流式响应的事件类型
+type MessageEvent string
+
+const (
+	MessageEventMessage        MessageEvent = "message"         // LLM 返回文本块
+	MessageEventMessageEnd     MessageEvent = "message_end"     // 消息结束，携带 usage 等元数据
+	MessageEventMessageReplace MessageEvent = "message_replace" // 内容审查替换整条消息
+	MessageEventError          MessageEvent = "error"           // 流式输出过程中出现异常
+	MessageEventPing           MessageEvent = "ping"            // 每 10s 一次的保活事件
+	MessageEventAgentMessage   MessageEvent = "agent_message"   // Agent 模式下返回的文本块
+	MessageEventMessageFile    MessageEvent = "message_file"    // 有新文件需要展示
+	MessageEventTTSMessage     MessageEvent = "tts_message"     // 开启自动播放时的语音块
+	MessageEventTTSMessageEnd  MessageEvent = "tts_message_end" // 语音流结束
+)
+
+// SendCompletionMessageRequest 文本生成请求
+type SendCompletionMessageRequest struct {
+	Inputs       map[string]interface{} `json:"inputs"` // 输入变量，completion 应用的查询内容通常放在 inputs.query
+	ResponseMode ResponseMode           `json:"response_mode"`
+	User         string                 `json:"user"`            // 用户标识
+	Files        []InputFile            `json:"files,omitempty"` // 上传的文件
+	Token        string                 `json:"-"`               // 应用访问令牌
+}
+
+// CompletionMessageResponse 文本生成阻塞响应
+type CompletionMessageResponse struct {
+	Event     string          `json:"event"`
+	TaskID    string          `json:"task_id"`
+	ID        string          `json:"id"`
+	MessageID string          `json:"message_id"`
+	Mode      string          `json:"mode"`
+	Answer    string          `json:"answer"`
+	Metadata  MessageMetadata `json:"metadata"`
+	CreatedAt int64           `json:"created_at"`
+}
+
+// CompletionMessageChunk 文本生成流式响应中的一个事件
+type CompletionMessageChunk struct {
+	Event     MessageEvent    `json:"event"`
+	TaskID    string          `json:"task_id"`
+	MessageID string          `json:"message_id"`
+	Answer    string          `json:"answer"`   // message / message_replace 事件的文本内容
+	Metadata  MessageMetadata `json:"metadata"` // message_end 事件的元数据
+	CreatedAt int64           `json:"created_at"`
+
+	// error 事件
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// MessageMetadata 消息元数据
+type MessageMetadata struct {
+	Usage              Usage               `json:"usage"`
+	RetrieverResources []RetrieverResource `json:"retriever_resources"`
+}
+
+// Usage 模型用量信息
+type Usage struct {
+	PromptTokens        int64   `json:"prompt_tokens"`
+	PromptUnitPrice     string  `json:"prompt_unit_price"`
+	PromptPriceUnit     string  `json:"prompt_price_unit"`
+	PromptPrice         string  `json:"prompt_price"`
+	CompletionTokens    int64   `json:"completion_tokens"`
+	CompletionUnitPrice string  `json:"completion_unit_price"`
+	CompletionPriceUnit string  `json:"completion_price_unit"`
+	CompletionPrice     string  `json:"completion_price"`
+	TotalTokens         int64   `json:"total_tokens"`
+	TotalPrice          string  `json:"total_price"`
+	Currency            string  `json:"currency"`
+	Latency             float64 `json:"latency"`
+}
+
+// RetrieverResource 引用和归属分段
+type RetrieverResource struct {
+	Position     int     `json:"position"`
+	DatasetID    string  `json:"dataset_id"`
+	DatasetName  string  `json:"dataset_name"`
+	DocumentID   string  `json:"document_id"`
+	DocumentName string  `json:"document_name"`
+	SegmentID    string  `json:"segment_id"`
+	Score        float64 `json:"score"`
+	Content      string  `json:"content"`
+}
@@ -0,0 +1,235 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ListConversations 分页获取用户的会话列表
+func (a *appClient) ListConversations(ctx context.Context, req *ListConversationsRequest) (*Response[ListConversationsResponse], error) {
+	var resp = &ListConversationsResponse{}
+	request := a.request().
+		WithContext(ctx).
+		SetQueryParam("user", req.User).
+		SetResult(&resp)
+	if req.LastID != "" {
+		request.SetQueryParam("last_id", req.LastID)
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	if req.SortBy != "" {
+		request.SetQueryParam("sort_by", string(req.SortBy))
+	}
+	response, err := request.Get("/conversations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ListConversationsResponse](response, resp), nil
+}
+
+// ListConversationMessages 分页获取会话的历史消息
+// 首页返回最新的 Limit 条消息，翻页时把当前页第一条消息的 ID 作为 FirstID
+func (a *appClient) ListConversationMessages(ctx context.Context, req *ListConversationMessagesRequest) (*Response[ListConversationMessagesResponse], error) {
+	var resp = &ListConversationMessagesResponse{}
+	request := a.request().
+		WithContext(ctx).
+		SetQueryParam("user", req.User).
+		SetQueryParam("conversation_id", req.ConversationID).
+		SetResult(&resp)
+	if req.FirstID != "" {
+		request.SetQueryParam("first_id", req.FirstID)
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	response, err := request.Get("/messages")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversation messages: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ListConversationMessagesResponse](response, resp), nil
+}
+
+// RenameConversation 重命名会话，AutoGenerate 为 true 时由模型生成名称
+func (a *appClient) RenameConversation(ctx context.Context, req *RenameConversationRequest) (*Response[Conversation], error) {
+	var resp = &Conversation{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post(fmt.Sprintf("/conversations/%s/name", req.ConversationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename conversation: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[Conversation](response, resp), nil
+}
+
+// DeleteConversation 删除会话
+func (a *appClient) DeleteConversation(ctx context.Context, req *DeleteConversationRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetAllowMethodDeletePayload(true).
+		SetBody(req).
+		SetResult(&resp).
+		Delete(fmt.Sprintf("/conversations/%s", req.ConversationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// ListConversationVariables 获取会话变量
+func (a *appClient) ListConversationVariables(ctx context.Context, req *ListConversationVariablesRequest) (*Response[ListConversationVariablesResponse], error) {
+	var resp = &ListConversationVariablesResponse{}
+	request := a.request().
+		WithContext(ctx).
+		SetQueryParam("user", req.User).
+		SetResult(&resp)
+	if req.LastID != "" {
+		request.SetQueryParam("last_id", req.LastID)
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	response, err := request.Get(fmt.Sprintf("/conversations/%s/variables", req.ConversationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversation variables: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ListConversationVariablesResponse](response, resp), nil
+}
+
+// ConversationSortBy 会话列表排序字段，- 前缀表示倒序
+type ConversationSortBy string
+
+const (
+	ConversationSortByCreatedAt     ConversationSortBy = "created_at"
+	ConversationSortByCreatedAtDesc ConversationSortBy = "-created_at"
+	ConversationSortByUpdatedAt     ConversationSortBy = "updated_at"
+	ConversationSortByUpdatedAtDesc ConversationSortBy = "-updated_at" // 默认
+)
+
+// ListConversationsRequest 获取会话列表请求
+type ListConversationsRequest struct {
+	User   string             // 用户标识
+	LastID string             // 当前页最后一条会话的ID，为空时获取首页
+	Limit  int                // 每页数量，默认 20
+	SortBy ConversationSortBy // 排序字段
+}
+
+// ListConversationsResponse 会话列表响应
+type ListConversationsResponse struct {
+	Limit   int            `json:"limit"`
+	HasMore bool           `json:"has_more"`
+	Data    []Conversation `json:"data"`
+}
+
+// Conversation 会话
+type Conversation struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Inputs       map[string]interface{} `json:"inputs"`
+	Status       string                 `json:"status"`
+	Introduction string                 `json:"introduction"`
+	CreatedAt    int64                  `json:"created_at"`
+	UpdatedAt    int64                  `json:"updated_at"`
+}
+
+// ListConversationMessagesRequest 获取会话历史消息请求
+type ListConversationMessagesRequest struct {
+	ConversationID string // 会话ID
+	User           string // 用户标识
+	FirstID        string // 当前页第一条消息的ID，为空时获取最新的消息
+	Limit          int    // 每页数量，默认 20
+}
+
+// ListConversationMessagesResponse 会话历史消息响应
+type ListConversationMessagesResponse struct {
+	Limit   int                   `json:"limit"`
+	HasMore bool                  `json:"has_more"`
+	Data    []ConversationMessage `json:"data"`
+}
+
+// ConversationMessage 会话中的一条消息
+type ConversationMessage struct {
+	ID                 string                 `json:"id"`
+	ConversationID     string                 `json:"conversation_id"`
+	Inputs             map[string]interface{} `json:"inputs"`
+	Query              string                 `json:"query"`
+	Answer             string                 `json:"answer"`
+	MessageFiles       []MessageFile          `json:"message_files"`
+	Feedback           *MessageFeedback       `json:"feedback"` // 未反馈时为 nil
+	RetrieverResources []RetrieverResource    `json:"retriever_resources"`
+	CreatedAt          int64                  `json:"created_at"`
+}
+
+// MessageFile 消息中的文件
+type MessageFile struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	BelongsTo string `json:"belongs_to"` // user / assistant
+}
+
+// MessageFeedback 消息的反馈
+type MessageFeedback struct {
+	Rating FeedbackRating `json:"rating"`
+}
+
+// RenameConversationRequest 重命名会话请求
+type RenameConversationRequest struct {
+	ConversationID string `json:"-"`              // 会话ID，不包含在JSON中
+	Name           string `json:"name,omitempty"` // 名称，AutoGenerate 为 true 时可为空
+	AutoGenerate   bool   `json:"auto_generate"`  // 自动生成名称
+	User           string `json:"user"`           // 用户标识
+}
+
+// DeleteConversationRequest 删除会话请求
+type DeleteConversationRequest struct {
+	ConversationID string `json:"-"`    // 会话ID，不包含在JSON中
+	User           string `json:"user"` // 用户标识
+}
+
+// ListConversationVariablesRequest 获取会话变量请求
+type ListConversationVariablesRequest struct {
+	ConversationID string // 会话ID
+	User           string // 用户标识
+	LastID         string // 当前页最后一个变量的ID
+	Limit          int    // 每页数量，默认 20
+}
+
+// ListConversationVariablesResponse 会话变量响应
+type ListConversationVariablesResponse struct {
+	Limit   int                         `json:"limit"`
+	HasMore bool                        `json:"has_more"`
+	Data    []ConversationVariableValue `json:"data"`
+}
+
+// ConversationVariableValue 会话变量的当前值
+type ConversationVariableValue struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	ValueType   string      `json:"value_type"`
+	Value       interface{} `json:"value"`
+	Description string      `json:"description"`
+	CreatedAt   int64       `json:"created_at"`
+	UpdatedAt   int64       `json:"updated_at"`
+}
@@ -0,0 +1,411 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"resty.dev/v3"
+	"time"
+)
+
+// ListNotionPages 获取已授权的 Notion 工作空间及其页面，用于选择要导入的页面
+func (c *client) ListNotionPages(ctx context.Context) (*Response[ListNotionPagesResponse], error) {
+	var resultErr error
+	var resp = &ListNotionPagesResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetResult(&resp).
+			Get("/console/api/notion/pre-import/pages")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to list notion pages: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to list notion pages with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ListNotionPagesResponse](finalResponse, resp), nil
+}
+
+// CreateByNotion 通过 Notion 页面创建文档
+// 需要先在 Dify 中完成 Notion 数据源授权
+func (c *client) CreateByNotion(ctx context.Context, req *CreateByNotionRequest) (*Response[CreateDocumentsResponse], error) {
+	if err := req.ProcessRule.Validate(req.DocForm); err != nil {
+		return nil, err
+	}
+	return c.createDocuments(ctx, req.DatasetsID, &createDocumentsInternalRequest{
+		DataSource: dataSource{
+			Type: DataSourceTypeNotionImport,
+			InfoList: dataSourceInfoList{
+				DataSourceType: DataSourceTypeNotionImport,
+				NotionInfoList: req.NotionInfoList,
+			},
+		},
+		IndexingTechnique: req.IndexingTechnique,
+		ProcessRule:       req.ProcessRule,
+		DocForm:           req.DocForm,
+		DocLanguage:       req.DocLanguage,
+	})
+}
+
+// StartWebsiteCrawl 开始抓取网站
+func (c *client) StartWebsiteCrawl(ctx context.Context, req *StartWebsiteCrawlRequest) (*Response[WebsiteCrawlJob], error) {
+	var resultErr error
+	var resp = &WebsiteCrawlJob{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Post("/console/api/website/crawl")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to start website crawl: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to start website crawl with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[WebsiteCrawlJob](finalResponse, resp), nil
+}
+
+// GetWebsiteCrawlStatus 获取网站抓取任务的状态和已抓取的页面
+func (c *client) GetWebsiteCrawlStatus(ctx context.Context, req *GetWebsiteCrawlStatusRequest) (*Response[WebsiteCrawlJob], error) {
+	var resultErr error
+	var resp = &WebsiteCrawlJob{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetQueryParam("provider", string(req.Provider)).
+			SetResult(&resp).
+			Get(fmt.Sprintf("/console/api/website/crawl/status/%s", req.JobID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to get website crawl status: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to get website crawl status with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[WebsiteCrawlJob](finalResponse, resp), nil
+}
+
+// WaitWebsiteCrawl 轮询网站抓取任务直到完成，interval 为 0 时默认 2 秒
+// 查询失败或任务以失败、取消状态结束时返回错误
+func (c *client) WaitWebsiteCrawl(ctx context.Context, req *GetWebsiteCrawlStatusRequest, interval time.Duration) (*WebsiteCrawlJob, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, err := c.GetWebsiteCrawlStatus(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.IsSuccess() {
+			return nil, fmt.Errorf("failed to get website crawl status with status %d: %s", resp.StatusCode(), resp.Message)
+		}
+		if resp.Result.Status == WebsiteCrawlStatusCompleted {
+			return resp.Result, nil
+		}
+		if resp.Result.Status.Failed() {
+			return resp.Result, fmt.Errorf("website crawl %s ended with status %s", req.JobID, resp.Result.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CreateByWebsite 通过网站抓取结果创建文档
+func (c *client) CreateByWebsite(ctx context.Context, req *CreateByWebsiteRequest) (*Response[CreateDocumentsResponse], error) {
+	if err := req.ProcessRule.Validate(req.DocForm); err != nil {
+		return nil, err
+	}
+	return c.createDocuments(ctx, req.DatasetsID, &createDocumentsInternalRequest{
+		DataSource: dataSource{
+			Type: DataSourceTypeWebsiteCrawl,
+			InfoList: dataSourceInfoList{
+				DataSourceType: DataSourceTypeWebsiteCrawl,
+				WebsiteInfoList: &WebsiteInfoList{
+					Provider:        req.Provider,
+					JobID:           req.JobID,
+					URLs:            req.URLs,
+					OnlyMainContent: req.OnlyMainContent,
+				},
+			},
+		},
+		IndexingTechnique: req.IndexingTechnique,
+		ProcessRule:       req.ProcessRule,
+		DocForm:           req.DocForm,
+		DocLanguage:       req.DocLanguage,
+	})
+}
+
+// createDocuments 通过控制台接口在已有知识库中创建文档
+func (c *client) createDocuments(ctx context.Context, datasetID string, req *createDocumentsInternalRequest) (*Response[CreateDocumentsResponse], error) {
+	var resultErr error
+	var resp = &CreateDocumentsResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Post(fmt.Sprintf("/console/api/datasets/%s/documents", datasetID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to create %s documents: %w", req.DataSource.Type, err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to create %s documents with status %d: %s", req.DataSource.Type, response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[CreateDocumentsResponse](finalResponse, resp), nil
+}
+
+// DataSourceType 文档数据源类型
+type DataSourceType string
+
+const (
+	DataSourceTypeUploadFile   DataSourceType = "upload_file"   // 上传文件
+	DataSourceTypeNotionImport DataSourceType = "notion_import" // Notion 导入
+	DataSourceTypeWebsiteCrawl DataSourceType = "website_crawl" // 网站抓取
+)
+
+// WebsiteCrawlProvider 网站抓取服务提供商
+type WebsiteCrawlProvider string
+
+const (
+	WebsiteCrawlProviderFirecrawl  WebsiteCrawlProvider = "firecrawl"
+	WebsiteCrawlProviderJinaReader WebsiteCrawlProvider = "jinareader"
+	WebsiteCrawlProviderWaterCrawl WebsiteCrawlProvider = "watercrawl"
+)
+
+// WebsiteCrawlStatus 网站抓取任务状态
+type WebsiteCrawlStatus string
+
+const (
+	WebsiteCrawlStatusActive    WebsiteCrawlStatus = "active"
+	WebsiteCrawlStatusCompleted WebsiteCrawlStatus = "completed"
+	WebsiteCrawlStatusFailed    WebsiteCrawlStatus = "failed"
+	WebsiteCrawlStatusCancelled WebsiteCrawlStatus = "cancelled"
+	WebsiteCrawlStatusError     WebsiteCrawlStatus = "error"
+)
+
+// Failed 抓取任务已失败结束
+func (s WebsiteCrawlStatus) Failed() bool {
+	return s == WebsiteCrawlStatusFailed || s == WebsiteCrawlStatusCancelled || s == WebsiteCrawlStatusError
+}
+
+// NotionInfo 一个 Notion 工作空间及其要导入的页面
+type NotionInfo struct {
+	WorkspaceID string       `json:"workspace_id"`
+	Pages       []NotionPage `json:"pages"`
+}
+
+// NotionPage Notion 页面
+type NotionPage struct {
+	PageID   string      `json:"page_id"`
+	PageName string      `json:"page_name"`
+	PageIcon interface{} `json:"page_icon"`
+	Type     string      `json:"type"` // page / database
+}
+
+// ListNotionPagesResponse Notion 页面列表响应
+type ListNotionPagesResponse struct {
+	NotionInfo []struct {
+		WorkspaceID   string `json:"workspace_id"`
+		WorkspaceName string `json:"workspace_name"`
+		WorkspaceIcon string `json:"workspace_icon"`
+		Pages         []struct {
+			NotionPage
+			ParentID string `json:"parent_id"`
+			IsBound  bool   `json:"is_bound"` // 是否已导入到知识库
+		} `json:"pages"`
+	} `json:"notion_info"`
+}
+
+// CreateByNotionRequest 通过 Notion 创建文档请求
+type CreateByNotionRequest struct {
+	DatasetsID        string
+	NotionInfoList    []NotionInfo
+	IndexingTechnique IndexingTechnique
+	DocForm           DocForm
+	DocLanguage       string
+	ProcessRule       ProcessRule
+}
+
+// StartWebsiteCrawlRequest 开始抓取网站请求
+type StartWebsiteCrawlRequest struct {
+	Provider WebsiteCrawlProvider `json:"provider"`
+	URL      string               `json:"url"`
+	Options  WebsiteCrawlOptions  `json:"options"`
+}
+
+// WebsiteCrawlOptions 网站抓取选项
+type WebsiteCrawlOptions struct {
+	CrawlSubPages   bool   `json:"crawl_sub_pages"`   // 是否抓取子页面
+	OnlyMainContent bool   `json:"only_main_content"` // 仅抽取正文
+	Includes        string `json:"includes"`          // 包含的路径，逗号分隔，例如 blog/*
+	Excludes        string `json:"excludes"`          // 排除的路径，逗号分隔
+	Limit           int    `json:"limit"`             // 最多抓取的页面数
+	MaxDepth        int    `json:"max_depth,omitempty"`
+	UseSitemap      bool   `json:"use_sitemap"`
+}
+
+// GetWebsiteCrawlStatusRequest 获取网站抓取状态请求
+type GetWebsiteCrawlStatusRequest struct {
+	Provider WebsiteCrawlProvider
+	JobID    string
+}
+
+// WebsiteCrawlJob 网站抓取任务
+type WebsiteCrawlJob struct {
+	Status        WebsiteCrawlStatus `json:"status"`
+	JobID         string             `json:"job_id"`
+	Total         int                `json:"total"`
+	Current       int                `json:"current"`
+	Data          []WebsitePage      `json:"data"`
+	TimeConsuming interface{}        `json:"time_consuming"`
+}
+
+// WebsitePage 抓取到的网页
+type WebsitePage struct {
+	Title       string `json:"title"`
+	Markdown    string `json:"markdown"`
+	Description string `json:"description"`
+	SourceURL   string `json:"source_url"`
+}
+
+// CreateByWebsiteRequest 通过网站抓取结果创建文档请求
+type CreateByWebsiteRequest struct {
+	DatasetsID        string
+	Provider          WebsiteCrawlProvider
+	JobID             string
+	URLs              []string // 要导入的页面地址，取自 WebsiteCrawlJob.Data 的 SourceURL
+	OnlyMainContent   bool
+	IndexingTechnique IndexingTechnique
+	DocForm           DocForm
+	DocLanguage       string
+	ProcessRule       ProcessRule
+}
+
+// WebsiteInfoList 网站抓取数据源
+type WebsiteInfoList struct {
+	Provider        WebsiteCrawlProvider `json:"provider"`
+	JobID           string               `json:"job_id"`
+	URLs            []string             `json:"urls"`
+	OnlyMainContent bool                 `json:"only_main_content"`
+}
+
+// CreateDocumentsResponse 通过数据源创建文档的响应
+type CreateDocumentsResponse struct {
+	Documents []Document `json:"documents"`
+	Batch     string     `json:"batch"`
+}
+
+// createDocumentsInternalRequest 内部使用的创建文档请求结构
+type createDocumentsInternalRequest struct {
+	DataSource        dataSource        `json:"data_source"`
+	IndexingTechnique IndexingTechnique `json:"indexing_technique"`
+	ProcessRule       ProcessRule       `json:"process_rule"`
+	DocForm           DocForm           `json:"doc_form"`
+	DocLanguage       string            `json:"doc_language"`
+}
+
+type dataSource struct {
+	Type     DataSourceType     `json:"type"`
+	InfoList dataSourceInfoList `json:"info_list"`
+}
+
+type dataSourceInfoList struct {
+	DataSourceType  DataSourceType   `json:"data_source_type"`
+	FileInfoList    *fileInfoList    `json:"file_info_list,omitempty"`
+	NotionInfoList  []NotionInfo     `json:"notion_info_list,omitempty"`
+	WebsiteInfoList *WebsiteInfoList `json:"website_info_list,omitempty"`
+}
+
+type fileInfoList struct {
+	FileIDs []string `json:"file_ids"`
+}
@@ -0,0 +1,122 @@
+package dify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateByNotion(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetID := newTestDataset(t, c)
+
+	resp, err := c.CreateByNotion(ctx, &CreateByNotionRequest{
+		DatasetsID: datasetID,
+		NotionInfoList: []NotionInfo{{
+			WorkspaceID: "ws-1",
+			Pages: []NotionPage{
+				{PageID: "p-1", PageName: "入职指南", Type: "page"},
+				{PageID: "p-2", PageName: "报销流程", Type: "page"},
+			},
+		}},
+		IndexingTechnique: IndexingTechniqueEconomy,
+		DocForm:           DocFormTextModel,
+		ProcessRule:       ProcessRule{Mode: ProcessModeAutomatic},
+	})
+	if err != nil {
+		t.Fatal("Failed to create documents by notion:", err)
+	}
+	if len(resp.Result.Documents) != 2 || resp.Result.Batch == "" {
+		t.Fatalf("Unexpected create by notion response: %+v", resp.Result)
+	}
+	if doc := resp.Result.Documents[0]; doc.Name != "入职指南" || doc.DataSourceType != string(DataSourceTypeNotionImport) {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+	if docs := server.Documents(datasetID); len(docs) != 2 {
+		t.Errorf("Expected 2 documents in dataset, got %d", len(docs))
+	}
+
+	if _, err := c.CreateByNotion(ctx, &CreateByNotionRequest{DatasetsID: "missing", ProcessRule: ProcessRule{Mode: ProcessModeAutomatic}}); err == nil {
+		t.Error("Expected error for unknown dataset")
+	}
+}
+
+func TestWebsiteCrawl(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetID := newTestDataset(t, c)
+
+	job, err := c.StartWebsiteCrawl(ctx, &StartWebsiteCrawlRequest{
+		Provider: WebsiteCrawlProviderJinaReader,
+		URL:      "https://example.com",
+		Options:  WebsiteCrawlOptions{CrawlSubPages: true, Limit: 10},
+	})
+	if err != nil {
+		t.Fatal("Failed to start website crawl:", err)
+	}
+	if job.Result.Status != WebsiteCrawlStatusActive || job.Result.JobID == "" {
+		t.Fatalf("Unexpected crawl job: %+v", job.Result)
+	}
+
+	result, err := c.WaitWebsiteCrawl(ctx, &GetWebsiteCrawlStatusRequest{Provider: WebsiteCrawlProviderJinaReader, JobID: job.Result.JobID}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal("Failed to wait for website crawl:", err)
+	}
+	if result.Status != WebsiteCrawlStatusCompleted || len(result.Data) != 2 {
+		t.Fatalf("Unexpected crawl result: %+v", result)
+	}
+
+	var urls []string
+	for _, page := range result.Data {
+		urls = append(urls, page.SourceURL)
+	}
+	resp, err := c.CreateByWebsite(ctx, &CreateByWebsiteRequest{
+		DatasetsID:        datasetID,
+		Provider:          WebsiteCrawlProviderJinaReader,
+		JobID:             job.Result.JobID,
+		URLs:              urls,
+		OnlyMainContent:   true,
+		IndexingTechnique: IndexingTechniqueEconomy,
+		DocForm:           DocFormTextModel,
+		ProcessRule:       ProcessRule{Mode: ProcessModeAutomatic},
+	})
+	if err != nil {
+		t.Fatal("Failed to create documents by website:", err)
+	}
+	if len(resp.Result.Documents) != 2 || resp.Result.Documents[1].Name != "https://example.com/about" {
+		t.Errorf("Unexpected create by website response: %+v", resp.Result)
+	}
+	if docs := server.Documents(datasetID); len(docs) != 2 || docs[0].DataSourceType != string(DataSourceTypeWebsiteCrawl) {
+		t.Errorf("Unexpected documents in dataset: %+v", docs)
+	}
+}
+
+func TestWaitWebsiteCrawlFailed(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := c.StartWebsiteCrawl(ctx, &StartWebsiteCrawlRequest{Provider: WebsiteCrawlProviderFirecrawl, URL: "https://fail.example.com"})
+	if err != nil {
+		t.Fatal("Failed to start website crawl:", err)
+	}
+	req := &GetWebsiteCrawlStatusRequest{Provider: WebsiteCrawlProviderFirecrawl, JobID: job.Result.JobID}
+	result, err := c.WaitWebsiteCrawl(ctx, req, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Fatalf("Expected crawl failure, got %v", err)
+	}
+	if result == nil || result.Status != WebsiteCrawlStatusFailed {
+		t.Errorf("Expected failed job to be returned, got %+v", result)
+	}
+
+	// 查询状态出错时不再轮询
+	server.FailNext("GET", "/console/api/website/crawl/status/"+job.Result.JobID, 500)
+	if _, err := c.WaitWebsiteCrawl(ctx, req, 10*time.Millisecond); err == nil || ctx.Err() != nil {
+		t.Errorf("Expected status error before the deadline, got %v", err)
+	}
+	if _, err := c.WaitWebsiteCrawl(ctx, &GetWebsiteCrawlStatusRequest{Provider: WebsiteCrawlProviderFirecrawl, JobID: "missing"}, 10*time.Millisecond); err == nil {
+		t.Error("Expected error for unknown crawl job")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 )
 
+// CreateEmptyDataset 创建空知识库，指定 TagIDs 时创建后绑定标签
+// 绑定标签失败时不会删除已创建的知识库，而是同时返回创建响应和绑定错误，调用方可根据响应中的知识库ID重试绑定或自行清理
 func (c *client) CreateEmptyDataset(ctx context.Context, req *CreateEmptyDatasetRequest) (*Response[CreateEmptyDatasetResponse], error) {
 	var resp = &CreateEmptyDatasetResponse{}
 	response, err := c.datasets().
@@ -18,7 +20,17 @@ func (c *client) CreateEmptyDataset(ctx context.Context, req *CreateEmptyDataset
 	if err != nil {
 		return nil, err
 	}
-	return buildResponse[CreateEmptyDatasetResponse](response, resp), nil
+	created := buildResponse[CreateEmptyDatasetResponse](response, resp)
+	if response.IsSuccess() && len(req.TagIDs) > 0 {
+		bindResp, err := c.BindDatasetTags(ctx, &BindDatasetTagsRequest{TagIDs: req.TagIDs, TargetID: resp.ID})
+		if err != nil {
+			return created, fmt.Errorf("failed to bind tags to dataset %s: %w", resp.ID, err)
+		}
+		if !bindResp.IsSuccess() {
+			return created, fmt.Errorf("failed to bind tags to dataset %s with status %d: %s", resp.ID, bindResp.StatusCode(), bindResp.Message)
+		}
+	}
+	return created, nil
 }
 
 type CreateEmptyDatasetRequest struct {
@@ -32,6 +44,7 @@ type CreateEmptyDatasetRequest struct {
 	EmbeddingModel         string            `json:"embedding_model"`
 	EmbeddingModelProvider string            `json:"embedding_model_provider"`
 	RetrievalModel         RetrievalModel    `json:"retrieval_model"`
+	TagIDs                 []string          `json:"-"` // 创建后绑定的标签ID
 }
 
 type RetrievalModel struct {
@@ -44,6 +57,8 @@ type RetrievalModel struct {
 	ScoreThresholdEnabled bool `json:"score_threshold_enabled"`
 	// 召回分数限制
 	ScoreThreshold float64 `json:"score_threshold"`
+	// 元数据过滤条件（仅检索时生效）
+	MetadataFilteringConditions *MetadataFilteringConditions `json:"metadata_filtering_conditions,omitempty"`
 }
 
 type RerankingModel struct {
@@ -72,19 +87,22 @@ type CreateEmptyDatasetResponse struct {
 }
 
 func (c *client) CreateByFile(ctx context.Context, req *CreateByFileRequest) (*Response[CreateByFileResponse], error) {
+	if err := req.ProcessRule.Validate(req.DocForm); err != nil {
+		return nil, err
+	}
 	bytes, jsonErr := json.Marshal(req)
 	if jsonErr != nil {
 		return nil, fmt.Errorf("failed to marshal CreateByFileRequest: %w", jsonErr)
 	}
 	var resp = &CreateByFileResponse{}
-	response, err := c.datasets().
-		WithContext(ctx).
-		SetFileReader("file", req.Filename, req.FileBody).
-		SetFormData(map[string]string{
-			"data": string(bytes),
-		}).
-		SetResult(&resp).
-		Post(fmt.Sprintf("/datasets/%s/document/create-by-file", req.DatasetsID))
+	response, err := c.postDocumentFile(ctx, fmt.Sprintf("/datasets/%s/document/create-by-file", req.DatasetsID), bytes, &documentFile{
+		Filename:    req.Filename,
+		Body:        req.FileBody,
+		Size:        req.FileSize,
+		MaxSize:     req.MaxFileSize,
+		ContentType: req.ContentType,
+		Progress:    req.Progress,
+	}, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -92,13 +110,21 @@ func (c *client) CreateByFile(ctx context.Context, req *CreateByFileRequest) (*R
 }
 
 type CreateByFileRequest struct {
-	DatasetsID        string
-	Filename          string
-	FileBody          io.Reader
-	IndexingTechnique IndexingTechnique `json:"indexing_technique"` // 索引方式
-	DocForm           DocForm           `json:"doc_form"`           // 索引内容的形式
-	DocLanguage       string            `json:"doc_language"`       //  在 Q&A 模式下，指定文档的语言，例如：English、Chinese
-	ProcessRule       ProcessRule       `json:"process_rule"`       // 文档处理规则
+	DatasetsID        string               `json:"-"`
+	Filename          string               `json:"-"`
+	FileBody          io.Reader            `json:"-"`                  // 文件内容，以流式上传，不会整体读入内存
+	FileSize          int64                `json:"-"`                  // 文件大小，为 0 时尝试从 FileBody 获取，用于提前检查大小限制和上传进度
+	MaxFileSize       int64                `json:"-"`                  // 文件大小限制（字节），为 0 时使用服务端的上传限制
+	ContentType       string               `json:"-"`                  // 文件的 MIME 类型，为空时根据扩展名或文件头识别
+	Progress          func(UploadProgress) `json:"-"`                  // 上传进度回调，在上传过程中被多次调用
+	IndexingTechnique IndexingTechnique    `json:"indexing_technique"` // 索引方式
+	DocForm           DocForm              `json:"doc_form"`           // 索引内容的形式
+	DocLanguage       string               `json:"doc_language"`       //  在 Q&A 模式下，指定文档的语言，例如：English、Chinese
+	ProcessRule       ProcessRule          `json:"process_rule"`       // 文档处理规则
+	// 以下参数仅在知识库首次上传文档时生效
+	RetrievalModel         *RetrievalModel `json:"retrieval_model,omitempty"`          // 检索模式，父子分段模式下检索子分段、召回父分段
+	EmbeddingModel         string          `json:"embedding_model,omitempty"`          // Embedding 模型名称
+	EmbeddingModelProvider string          `json:"embedding_model_provider,omitempty"` // Embedding 模型供应商
 }
 
 type ProcessRule struct {
@@ -112,8 +138,69 @@ type PreProcessingRules struct {
 }
 
 type ProcessRules struct {
-	PreProcessingRules []PreProcessingRules `json:"pre_processing_rules"` // 文档预处理规则
-	Segmentation       Segmentation         `json:"segmentation"`         // 分段规则
+	PreProcessingRules   []PreProcessingRules `json:"pre_processing_rules"`            // 文档预处理规则
+	Segmentation         Segmentation         `json:"segmentation"`                    // 分段规则，父子分段模式下为父分段规则
+	ParentMode           ParentMode           `json:"parent_mode,omitempty"`           // 父分段的召回方式，仅父子分段模式
+	SubchunkSegmentation *Segmentation        `json:"subchunk_segmentation,omitempty"` // 子分段规则，仅父子分段模式
+}
+
+// HierarchicalProcessRule 创建父子分段的处理规则，parentMode 为 full-doc 时 parent 仅保留预处理规则的意义
+func HierarchicalProcessRule(parentMode ParentMode, parent, child Segmentation, preProcessingRules ...PreProcessingRules) ProcessRule {
+	return ProcessRule{
+		Mode: ProcessModeHierarchical,
+		Rules: ProcessRules{
+			PreProcessingRules:   preProcessingRules,
+			Segmentation:         parent,
+			ParentMode:           parentMode,
+			SubchunkSegmentation: &child,
+		},
+	}
+}
+
+// Validate 校验处理规则与文档形式是否匹配
+// 父子分段（DocFormHierarchicalModel）必须使用 hierarchical 模式，并设置父分段召回方式和子分段规则
+func (p *ProcessRule) Validate(docForm DocForm) error {
+	hierarchical := docForm == DocFormHierarchicalModel
+	switch p.Mode {
+	case "", ProcessModeAutomatic:
+		if hierarchical {
+			return fmt.Errorf("doc form %s requires process rule mode %s", docForm, ProcessModeHierarchical)
+		}
+		return nil
+	case ProcessModeManual:
+		if hierarchical {
+			return fmt.Errorf("doc form %s requires process rule mode %s", docForm, ProcessModeHierarchical)
+		}
+		return p.Rules.Segmentation.validate("segmentation")
+	case ProcessModeHierarchical:
+		if !hierarchical {
+			return fmt.Errorf("process rule mode %s requires doc form %s", p.Mode, DocFormHierarchicalModel)
+		}
+	default:
+		return fmt.Errorf("invalid process rule mode %q", p.Mode)
+	}
+
+	sub := p.Rules.SubchunkSegmentation
+	if sub == nil {
+		return fmt.Errorf("subchunk_segmentation is required in %s mode", ProcessModeHierarchical)
+	}
+	if err := sub.validate("subchunk_segmentation"); err != nil {
+		return err
+	}
+	switch p.Rules.ParentMode {
+	case ParentModeFullDoc:
+		return nil
+	case ParentModeParagraph:
+		if err := p.Rules.Segmentation.validate("segmentation"); err != nil {
+			return err
+		}
+		if sub.MaxTokens > p.Rules.Segmentation.MaxTokens {
+			return fmt.Errorf("subchunk_segmentation max_tokens %d exceeds parent max_tokens %d", sub.MaxTokens, p.Rules.Segmentation.MaxTokens)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid parent_mode %q", p.Rules.ParentMode)
+	}
 }
 
 type Segmentation struct {
@@ -122,30 +209,43 @@ type Segmentation struct {
 	ChunkOverlap int64  `json:"chunk_overlap"` // 分段重叠长度（token），默认为 0
 }
 
+func (s *Segmentation) validate(name string) error {
+	if s.MaxTokens <= 0 {
+		return fmt.Errorf("%s max_tokens must be positive", name)
+	}
+	if s.ChunkOverlap < 0 || s.ChunkOverlap >= s.MaxTokens {
+		return fmt.Errorf("%s chunk_overlap %d must be in [0, max_tokens)", name, s.ChunkOverlap)
+	}
+	return nil
+}
+
 type CreateByFileResponse struct {
-	Document struct {
-		Id             string `json:"id"`
-		Position       int    `json:"position"`
-		DataSourceType string `json:"data_source_type"`
-		DataSourceInfo struct {
-			UploadFileId string `json:"upload_file_id"`
-		} `json:"data_source_info"`
-		DatasetProcessRuleId string      `json:"dataset_process_rule_id"`
-		Name                 string      `json:"name"`
-		CreatedFrom          string      `json:"created_from"`
-		CreatedBy            string      `json:"created_by"`
-		CreatedAt            int64       `json:"created_at"`
-		Tokens               int64       `json:"tokens"`
-		IndexingStatus       string      `json:"indexing_status"`
-		Error                interface{} `json:"error"`
-		Enabled              bool        `json:"enabled"`
-		DisabledAt           int64       `json:"disabled_at"`
-		DisabledBy           string      `json:"disabled_by"`
-		Archived             bool        `json:"archived"`
-		DisplayStatus        string      `json:"display_status"`
-		WordCount            int64       `json:"word_count"`
-		HitCount             int64       `json:"hit_count"`
-		DocForm              string      `json:"doc_form"`
-	} `json:"document"`
-	Batch string `json:"batch"`
+	Document Document `json:"document"`
+	Batch    string   `json:"batch"`
+}
+
+// Document 知识库中的文档
+type Document struct {
+	Id             string `json:"id"`
+	Position       int    `json:"position"`
+	DataSourceType string `json:"data_source_type"`
+	DataSourceInfo struct {
+		UploadFileId string `json:"upload_file_id"`
+	} `json:"data_source_info"`
+	DatasetProcessRuleId string      `json:"dataset_process_rule_id"`
+	Name                 string      `json:"name"`
+	CreatedFrom          string      `json:"created_from"`
+	CreatedBy            string      `json:"created_by"`
+	CreatedAt            int64       `json:"created_at"`
+	Tokens               int64       `json:"tokens"`
+	IndexingStatus       string      `json:"indexing_status"`
+	Error                interface{} `json:"error"`
+	Enabled              bool        `json:"enabled"`
+	DisabledAt           int64       `json:"disabled_at"`
+	DisabledBy           string      `json:"disabled_by"`
+	Archived             bool        `json:"archived"`
+	DisplayStatus        string      `json:"display_status"`
+	WordCount            int64       `json:"word_count"`
+	HitCount             int64       `json:"hit_count"`
+	DocForm              string      `json:"doc_form"`
 }
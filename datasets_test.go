@@ -0,0 +1,60 @@
+package dify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProcessRuleValidate(t *testing.T) {
+	parent := Segmentation{Separator: "\n\n", MaxTokens: 1024, ChunkOverlap: 0}
+	child := Segmentation{Separator: "\n", MaxTokens: 256}
+
+	valid := []struct {
+		docForm DocForm
+		rule    ProcessRule
+	}{
+		{DocFormTextModel, ProcessRule{Mode: ProcessModeAutomatic}},
+		{DocFormTextModel, ProcessRule{Mode: ProcessModeManual, Rules: ProcessRules{Segmentation: parent}}},
+		{DocFormHierarchicalModel, HierarchicalProcessRule(ParentModeParagraph, parent, child)},
+		{DocFormHierarchicalModel, HierarchicalProcessRule(ParentModeFullDoc, Segmentation{}, child)},
+	}
+	for i, c := range valid {
+		if err := c.rule.Validate(c.docForm); err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+		}
+	}
+
+	invalid := []struct {
+		docForm DocForm
+		rule    ProcessRule
+	}{
+		{DocFormHierarchicalModel, ProcessRule{Mode: ProcessModeManual, Rules: ProcessRules{Segmentation: parent}}},
+		{DocFormTextModel, HierarchicalProcessRule(ParentModeParagraph, parent, child)},
+		{DocFormHierarchicalModel, ProcessRule{Mode: ProcessModeHierarchical, Rules: ProcessRules{Segmentation: parent, ParentMode: ParentModeParagraph}}},
+		{DocFormHierarchicalModel, HierarchicalProcessRule("section", parent, child)},
+		{DocFormHierarchicalModel, HierarchicalProcessRule(ParentModeParagraph, Segmentation{MaxTokens: 128}, child)},
+		{DocFormTextModel, ProcessRule{Mode: ProcessModeManual, Rules: ProcessRules{Segmentation: Segmentation{MaxTokens: 100, ChunkOverlap: 100}}}},
+	}
+	for i, c := range invalid {
+		if err := c.rule.Validate(c.docForm); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestHierarchicalProcessRuleJSON(t *testing.T) {
+	rule := HierarchicalProcessRule(ParentModeParagraph,
+		Segmentation{Separator: "\n\n", MaxTokens: 1024},
+		Segmentation{Separator: "\n", MaxTokens: 256},
+		PreProcessingRules{ID: PreProcessingRulesIDRemoveExtraSpaces, Enabled: true})
+	data, err := json.Marshal(&rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"mode":"hierarchical","rules":{"pre_processing_rules":[{"id":"remove_extra_spaces","enabled":true}],` +
+		`"segmentation":{"separator":"\n\n","max_tokens":1024,"chunk_overlap":0},"parent_mode":"paragraph",` +
+		`"subchunk_segmentation":{"separator":"\n","max_tokens":256,"chunk_overlap":0}}}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
@@ -0,0 +1,146 @@
+package difytest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+type annotation struct {
+	ID        string `json:"id"`
+	Question  string `json:"question"`
+	Answer    string `json:"answer"`
+	HitCount  int    `json:"hit_count"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// Annotations 返回应用全部标注的问题，按创建顺序排列
+func (s *Server) Annotations(appID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.apps[appID]
+	if !ok {
+		return nil
+	}
+	var questions []string
+	for _, item := range a.annotations {
+		questions = append(questions, item.Question)
+	}
+	return questions
+}
+
+func (s *Server) registerAnnotationRoutes() {
+	s.handle(http.MethodGet, "/v1/apps/annotations", authApp, s.listAnnotations)
+	s.handle(http.MethodPost, "/v1/apps/annotations", authApp, s.createAnnotation)
+	s.handle(http.MethodPut, "/v1/apps/annotations/{annotation_id}", authApp, s.updateAnnotation)
+	s.handle(http.MethodDelete, "/v1/apps/annotations/{annotation_id}", authApp, s.deleteAnnotation)
+	s.handle(http.MethodPost, "/v1/apps/annotation-reply/{action}", authApp, s.setAnnotationReply)
+	s.handle(http.MethodGet, "/v1/apps/annotation-reply/{action}/status/{job_id}", authApp, s.annotationReplyStatus)
+}
+
+// appByToken 返回应用 API 密钥对应的应用，调用方需持有锁
+func (s *Server) appByToken(r *http.Request) *app {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if key, ok := s.appAPIKeys[token]; ok {
+		return s.apps[key.appID]
+	}
+	return nil
+}
+
+func (s *Server) listAnnotations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.appByToken(r)
+	keyword := r.URL.Query().Get("keyword")
+	var all []*annotation
+	for _, item := range a.annotations {
+		if keyword == "" || strings.Contains(item.Question, keyword) || strings.Contains(item.Answer, keyword) {
+			all = append(all, item)
+		}
+	}
+	page, limit := pagination(r)
+	data, hasMore := paginate(all, page, limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":     data,
+		"has_more": hasMore,
+		"limit":    limit,
+		"total":    len(all),
+		"page":     page,
+	})
+}
+
+func (s *Server) createAnnotation(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.appByToken(r)
+	item := &annotation{ID: s.nextID(), Question: req.Question, Answer: req.Answer, CreatedAt: time.Now().Unix()}
+	a.annotations = append(a.annotations, item)
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) updateAnnotation(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range s.appByToken(r).annotations {
+		if item.ID == params["annotation_id"] {
+			item.Question = req.Question
+			item.Answer = req.Answer
+			writeJSON(w, http.StatusOK, item)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not_found", "Annotation not found.")
+}
+
+func (s *Server) deleteAnnotation(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.appByToken(r)
+	for i, item := range a.annotations {
+		if item.ID == params["annotation_id"] {
+			a.annotations = append(a.annotations[:i], a.annotations[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not_found", "Annotation not found.")
+}
+
+// setAnnotationReply 立即完成开关任务
+func (s *Server) setAnnotationReply(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req map[string]interface{}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.appByToken(r)
+	switch params["action"] {
+	case "enable":
+		a.annotationReply = req
+	case "disable":
+		a.annotationReply = nil
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_param", "action must be enable or disable")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"job_id": s.nextID(), "job_status": "waiting"})
+}
+
+func (s *Server) annotationReplyStatus(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	writeJSON(w, http.StatusOK, map[string]string{"job_id": params["job_id"], "job_status": "completed", "error_msg": ""})
+}
@@ -0,0 +1,50 @@
+package difytest
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// audioSizeLimit 语音转文字的文件大小限制，与 Dify 一致为 15MB
+const audioSizeLimit = 15 << 20
+
+// audioExtensions 语音转文字支持的格式
+var audioExtensions = []string{"mp3", "mp4", "mpeg", "mpga", "m4a", "wav", "webm", "amr"}
+
+func (s *Server) registerAudioRoutes() {
+	s.handle(http.MethodPost, "/v1/audio-to-text", authApp, s.audioToText)
+}
+
+// audioToText 以文件内容作为识别结果返回
+func (s *Server) audioToText(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "no_audio_uploaded", "Please upload your audio.")
+		return
+	}
+	defer file.Close()
+	if r.FormValue("user") == "" {
+		writeError(w, http.StatusBadRequest, "invalid_param", "user is required")
+		return
+	}
+	if !containsString(audioExtensions, strings.TrimPrefix(strings.ToLower(path.Ext(header.Filename)), ".")) {
+		writeError(w, http.StatusUnsupportedMediaType, "unsupported_audio_type", "Audio type not allowed.")
+		return
+	}
+	content, err := io.ReadAll(io.LimitReader(file, audioSizeLimit+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return
+	}
+	if len(content) > audioSizeLimit {
+		writeError(w, http.StatusRequestEntityTooLarge, "audio_too_large", "Audio size exceeded.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"text": string(content)})
+}
@@ -0,0 +1,375 @@
+package difytest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CassetteMode 录制或回放
+type CassetteMode string
+
+const (
+	CassetteModeReplay CassetteMode = "replay" // 从磁带文件回放，不访问网络
+	CassetteModeRecord CassetteMode = "record" // 代理到真实 Dify 并录制
+)
+
+const redacted = "[REDACTED]"
+
+// 需要脱敏的内容，磁带文件中只保留前缀
+var scrubPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`"(password|access_token|refresh_token)"(\s*):(\s*)"[^"]*"`), `"$1"$2:$3"` + redacted + `"`},
+	{regexp.MustCompile(`\bapp-[A-Za-z0-9]+`), "app-" + redacted},
+	{regexp.MustCompile(`\bdataset-[A-Za-z0-9]+`), "dataset-" + redacted},
+	{regexp.MustCompile(`(?i)\bBearer\s+[^\s"]+`), "Bearer " + redacted},
+}
+
+// Scrub 去除文本中的密码、access/refresh token 以及 app-/dataset- 开头的 API 密钥
+func Scrub(s string) string {
+	for _, p := range scrubPatterns {
+		s = p.pattern.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// Cassette 录制的请求/响应序列
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction 一次请求及其响应
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest 录制的请求，只用于回放时匹配和排查问题
+type RecordedRequest struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Query  string            `json:"query,omitempty"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"` // 仅记录 JSON/文本，其他类型只记录大小
+}
+
+// RecordedResponse 录制的响应，SSE 响应记录在 Events 中
+type RecordedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+	Events []RecordedEvent   `json:"events,omitempty"`
+}
+
+// RecordedEvent SSE 事件
+type RecordedEvent struct {
+	Delay int64  `json:"delay_ms"` // 距上一个事件（或响应头）的毫秒数
+	Data  string `json:"data"`     // 事件的原始行，不包含结尾的空行
+}
+
+// LoadCassette 读取磁带文件
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save 写入磁带文件，必要时创建目录
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// CassetteServer 录制或回放磁带的服务，客户端使用 URL 代替真实的 Dify 地址
+type CassetteServer struct {
+	*httptest.Server
+
+	mode      CassetteMode
+	path      string
+	target    string
+	timeScale float64
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// CassetteOption 配置磁带服务
+type CassetteOption func(*CassetteServer)
+
+// WithTimeScale 按比例缩放回放时 SSE 事件之间的延迟，0 表示不等待，默认按录制时的节奏回放
+func WithTimeScale(scale float64) CassetteOption {
+	return func(s *CassetteServer) {
+		s.timeScale = scale
+	}
+}
+
+// NewCassetteServer 启动磁带服务
+// 录制模式下把请求转发到 target 并在 Close 时写入 path；回放模式下从 path 读取磁带，target 被忽略
+func NewCassetteServer(mode CassetteMode, path, target string, opts ...CassetteOption) (*CassetteServer, error) {
+	s := &CassetteServer{
+		mode:      mode,
+		path:      path,
+		target:    strings.TrimSuffix(target, "/"),
+		timeScale: 1,
+		cassette:  &Cassette{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	var handler http.HandlerFunc
+	switch mode {
+	case CassetteModeRecord:
+		if s.target == "" {
+			return nil, fmt.Errorf("record mode requires a target Dify URL")
+		}
+		handler = s.record
+	case CassetteModeReplay:
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		s.cassette = cassette
+		s.used = make([]bool, len(cassette.Interactions))
+		handler = s.replay
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", mode)
+	}
+	s.Server = httptest.NewServer(handler)
+	return s, nil
+}
+
+// NewCassetteServerFromEnv 根据环境变量启动磁带服务
+// DIFY_CASSETTE=record 时录制到 DIFY_URL 指向的 Dify，否则回放
+func NewCassetteServerFromEnv(path string, opts ...CassetteOption) (*CassetteServer, error) {
+	if CassetteMode(os.Getenv("DIFY_CASSETTE")) == CassetteModeRecord {
+		return NewCassetteServer(CassetteModeRecord, path, os.Getenv("DIFY_URL"), opts...)
+	}
+	return NewCassetteServer(CassetteModeReplay, path, "", opts...)
+}
+
+// Mode 返回磁带服务的模式
+func (s *CassetteServer) Mode() CassetteMode {
+	return s.mode
+}
+
+// Close 关闭服务，录制模式下写入磁带文件
+func (s *CassetteServer) Close() error {
+	s.Server.Close()
+	if s.mode != CassetteModeRecord {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cassette.Save(s.path)
+}
+
+// Unused 返回回放模式下未被请求的录制记录，用于检查测试是否与磁带一致
+func (s *CassetteServer) Unused() []*Interaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var unused []*Interaction
+	for i, used := range s.used {
+		if !used {
+			unused = append(unused, s.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+func (s *CassetteServer) record(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return
+	}
+	upstream, err := http.NewRequestWithContext(r.Context(), r.Method, s.target+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "cassette_record_failed", err.Error())
+		return
+	}
+	upstream.Header = r.Header.Clone()
+	resp, err := http.DefaultClient.Do(upstream)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "cassette_record_failed", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	interaction := &Interaction{
+		Request: RecordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  Scrub(r.URL.RawQuery),
+			Header: recordHeader(r.Header, "Authorization", "Content-Type"),
+			Body:   recordBody(r.Header.Get("Content-Type"), body),
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: recordHeader(resp.Header, "Content-Type"),
+		},
+	}
+
+	for k, v := range resp.Header {
+		if k != "Content-Length" && k != "Set-Cookie" {
+			w.Header()[k] = v
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	if isEventStream(resp.Header.Get("Content-Type")) {
+		interaction.Response.Events = relayEvents(w, resp.Body)
+	} else {
+		respBody, _ := io.ReadAll(resp.Body)
+		_, _ = w.Write(respBody)
+		interaction.Response.Body = recordBody(resp.Header.Get("Content-Type"), respBody)
+	}
+
+	s.mu.Lock()
+	s.cassette.Interactions = append(s.cassette.Interactions, interaction)
+	s.mu.Unlock()
+}
+
+// relayEvents 把 SSE 事件转发给客户端，同时记录每个事件及其间隔
+func relayEvents(w http.ResponseWriter, body io.Reader) []RecordedEvent {
+	flusher, _ := w.(http.Flusher)
+	var events []RecordedEvent
+	var lines []string
+	last := time.Now()
+
+	reader := bufio.NewReader(body)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			_, _ = io.WriteString(w, line)
+			trimmed := strings.TrimRight(line, "\r\n")
+			if trimmed == "" {
+				if flusher != nil {
+					flusher.Flush()
+				}
+				if len(lines) > 0 {
+					now := time.Now()
+					events = append(events, RecordedEvent{Delay: now.Sub(last).Milliseconds(), Data: Scrub(strings.Join(lines, "\n"))})
+					last = now
+					lines = nil
+				}
+			} else {
+				lines = append(lines, trimmed)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if len(lines) > 0 {
+		events = append(events, RecordedEvent{Delay: time.Since(last).Milliseconds(), Data: Scrub(strings.Join(lines, "\n"))})
+	}
+	return events
+}
+
+func (s *CassetteServer) replay(w http.ResponseWriter, r *http.Request) {
+	interaction := s.match(r)
+	if interaction == nil {
+		writeError(w, http.StatusNotFound, "cassette_miss", fmt.Sprintf("no recorded interaction for %s %s", r.Method, r.URL.RequestURI()))
+		return
+	}
+	for k, v := range interaction.Response.Header {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(interaction.Response.Status)
+
+	if len(interaction.Response.Events) == 0 {
+		_, _ = io.WriteString(w, interaction.Response.Body)
+		return
+	}
+	flusher, _ := w.(http.Flusher)
+	for _, event := range interaction.Response.Events {
+		if delay := time.Duration(float64(event.Delay) * s.timeScale * float64(time.Millisecond)); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if _, err := io.WriteString(w, event.Data+"\n\n"); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// match 按录制顺序返回第一条方法、路径和查询参数都相同且未使用的记录
+func (s *CassetteServer) match(r *http.Request) *Interaction {
+	query := Scrub(r.URL.RawQuery)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, interaction := range s.cassette.Interactions {
+		if s.used[i] {
+			continue
+		}
+		req := interaction.Request
+		if req.Method == r.Method && req.Path == r.URL.Path && req.Query == query {
+			s.used[i] = true
+			return interaction
+		}
+	}
+	return nil
+}
+
+func recordHeader(header http.Header, keys ...string) map[string]string {
+	recorded := map[string]string{}
+	for _, key := range keys {
+		if v := header.Get(key); v != "" {
+			recorded[key] = Scrub(v)
+		}
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	return recorded
+}
+
+// recordBody 记录 JSON 和文本内容，文件等二进制内容只记录类型和大小
+func recordBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasPrefix(mediaType, "text/") {
+		return Scrub(string(body))
+	}
+	return fmt.Sprintf("[%s body, %d bytes]", mediaType, len(body))
+}
+
+func isEventStream(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "text/event-stream"
+}
@@ -0,0 +1,120 @@
+package difytest
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScrub(t *testing.T) {
+	cases := map[string]string{
+		`{"email":"a@b.c","password": "secret"}`:         `{"email":"a@b.c","password": "[REDACTED]"}`,
+		`{"access_token":"eyJ.x.y","refresh_token":"r"}`: `{"access_token":"[REDACTED]","refresh_token":"[REDACTED]"}`,
+		`{"token":"app-AbC123"}`:                         `{"token":"app-[REDACTED]"}`,
+		"Bearer dataset-xyz":                             "Bearer [REDACTED]",
+		"dataset-xyz and dataset_id":                     "dataset-[REDACTED] and dataset_id",
+	}
+	for in, want := range cases {
+		if got := Scrub(in); got != want {
+			t.Errorf("Scrub(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	upstream := NewServer()
+	defer upstream.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	login := func(baseURL string) string {
+		body := `{"email":"` + DefaultEmail + `","password":"` + DefaultPassword + `"}`
+		resp, err := http.Post(baseURL+"/console/api/login", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	recorder, err := NewCassetteServer(CassetteModeRecord, path, upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := login(recorder.URL)
+	if !strings.Contains(recorded, "access-") {
+		t.Fatalf("record mode should pass the real response through, got %s", recorded)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cassette.Interactions) != 1 {
+		t.Fatalf("expected 1 interaction, got %d", len(cassette.Interactions))
+	}
+	interaction := cassette.Interactions[0]
+	if strings.Contains(interaction.Request.Body, `"password":"`+DefaultPassword) || strings.Contains(interaction.Response.Body, "access-") {
+		t.Fatalf("secrets were not scrubbed: %+v", interaction)
+	}
+
+	// 回放时增加一个 SSE 响应，验证事件按顺序输出
+	cassette.Interactions = append(cassette.Interactions, &Interaction{
+		Request: RecordedRequest{Method: http.MethodPost, Path: "/v1/workflows/run"},
+		Response: RecordedResponse{
+			Status: http.StatusOK,
+			Header: map[string]string{"Content-Type": "text/event-stream"},
+			Events: []RecordedEvent{
+				{Delay: 5, Data: `data: {"event":"workflow_started"}`},
+				{Delay: 5, Data: `data: {"event":"workflow_finished"}`},
+			},
+		},
+	})
+	if err := cassette.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := NewCassetteServer(CassetteModeReplay, path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+
+	if replayed := login(player.URL); !strings.Contains(replayed, `"access_token":"[REDACTED]"`) {
+		t.Fatalf("unexpected replayed body %s", replayed)
+	}
+	resp, err := http.Post(player.URL+"/v1/workflows/run", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if scanner.Text() != "" {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	if len(lines) != 2 || !strings.Contains(lines[1], "workflow_finished") {
+		t.Fatalf("unexpected replayed stream %v", lines)
+	}
+	if len(player.Unused()) != 0 {
+		t.Fatalf("expected every interaction to be replayed")
+	}
+
+	// 已回放的记录不会再次匹配
+	resp, err = http.Post(player.URL+"/v1/workflows/run", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a cassette miss, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,187 @@
+package difytest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// crawlJob 网站抓取任务，查询两次状态后结束；URL 包含 fail 时以 failed 状态结束
+type crawlJob struct {
+	ID       string
+	Provider string
+	URL      string
+	Limit    int
+	polls    int
+}
+
+func (s *Server) registerDataSourceRoutes() {
+	s.handle(http.MethodPost, "/console/api/website/crawl", authConsole, s.startWebsiteCrawl)
+	s.handle(http.MethodGet, "/console/api/website/crawl/status/{job_id}", authConsole, s.websiteCrawlStatus)
+	s.handle(http.MethodPost, "/console/api/datasets/{dataset_id}/documents", authConsole, s.createDocuments)
+}
+
+func (s *Server) startWebsiteCrawl(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		Provider string `json:"provider"`
+		URL      string `json:"url"`
+		Options  struct {
+			Limit int `json:"limit"`
+		} `json:"options"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Provider == "" || req.URL == "" {
+		writeError(w, http.StatusBadRequest, "invalid_param", "provider and url are required")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := &crawlJob{ID: s.nextID(), Provider: req.Provider, URL: req.URL, Limit: req.Options.Limit}
+	s.crawlJobs[job.ID] = job
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "active", "job_id": job.ID})
+}
+
+func (s *Server) websiteCrawlStatus(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.crawlJobs[params["job_id"]]
+	if !ok || job.Provider != r.URL.Query().Get("provider") {
+		writeError(w, http.StatusNotFound, "not_found", "Crawl job not found.")
+		return
+	}
+	job.polls++
+	pages := job.pages()
+	status := "active"
+	current := 1
+	switch {
+	case job.polls < 2:
+	case strings.Contains(job.URL, "fail"):
+		status = "failed"
+	default:
+		status = "completed"
+		current = len(pages)
+	}
+	data := []map[string]string{}
+	if status == "completed" {
+		for _, url := range pages {
+			data = append(data, map[string]string{
+				"title":       url,
+				"markdown":    "# " + url,
+				"description": "difytest page",
+				"source_url":  url,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":         status,
+		"job_id":         job.ID,
+		"total":          len(pages),
+		"current":        current,
+		"data":           data,
+		"time_consuming": float64(job.polls),
+	})
+}
+
+// pages 返回任务抓取到的页面地址
+func (j *crawlJob) pages() []string {
+	pages := []string{j.URL, strings.TrimRight(j.URL, "/") + "/about"}
+	if j.Limit > 0 && len(pages) > j.Limit {
+		pages = pages[:j.Limit]
+	}
+	return pages
+}
+
+// createDocuments 通过 Notion 页面或网站抓取结果创建文档
+func (s *Server) createDocuments(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		DataSource struct {
+			Type     string `json:"type"`
+			InfoList struct {
+				NotionInfoList []struct {
+					WorkspaceID string `json:"workspace_id"`
+					Pages       []struct {
+						PageID   string `json:"page_id"`
+						PageName string `json:"page_name"`
+					} `json:"pages"`
+				} `json:"notion_info_list"`
+				WebsiteInfoList *struct {
+					Provider string   `json:"provider"`
+					JobID    string   `json:"job_id"`
+					URLs     []string `json:"urls"`
+				} `json:"website_info_list"`
+			} `json:"info_list"`
+		} `json:"data_source"`
+		DocForm string `json:"doc_form"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	var names []string
+	info := req.DataSource.InfoList
+	switch req.DataSource.Type {
+	case "notion_import":
+		for _, workspace := range info.NotionInfoList {
+			for _, page := range workspace.Pages {
+				names = append(names, page.PageName)
+			}
+		}
+	case "website_crawl":
+		if info.WebsiteInfoList == nil {
+			writeError(w, http.StatusBadRequest, "invalid_param", "website_info_list is required")
+			return
+		}
+		job, ok := s.crawlJobs[info.WebsiteInfoList.JobID]
+		if !ok || job.Provider != info.WebsiteInfoList.Provider {
+			writeError(w, http.StatusBadRequest, "invalid_param", "Crawl job not found.")
+			return
+		}
+		names = info.WebsiteInfoList.URLs
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_param", fmt.Sprintf("unsupported data source type %q", req.DataSource.Type))
+		return
+	}
+	if len(names) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_param", "no documents to create")
+		return
+	}
+
+	docForm := req.DocForm
+	if docForm == "" {
+		docForm = "text_model"
+	}
+	batch := fmt.Sprintf("%014d", s.seq+1)
+	docs := make([]*Document, 0, len(names))
+	for _, name := range names {
+		doc := &Document{
+			ID:             s.nextID(),
+			Position:       len(ds.documents) + 1,
+			DataSourceType: req.DataSource.Type,
+			Name:           name,
+			CreatedFrom:    "web",
+			CreatedAt:      time.Now().Unix(),
+			IndexingStatus: "completed",
+			Enabled:        true,
+			DisplayStatus:  "available",
+			DocForm:        docForm,
+			Batch:          batch,
+		}
+		ds.documents = append(ds.documents, doc)
+		docs = append(docs, doc)
+	}
+	ds.DocumentCount = len(ds.documents)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"documents": docs,
+		"batch":     batch,
+	})
+}
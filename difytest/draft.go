@@ -0,0 +1,124 @@
+package difytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (s *Server) registerDraftWorkflowRoutes() {
+	s.handle(http.MethodPost, "/console/api/apps/{app_id}/workflows/draft/run", authConsole, s.runDraftWorkflow)
+	s.handle(http.MethodPost, "/console/api/apps/{app_id}/workflows/draft/nodes/{node_id}/run", authConsole, s.runDraftWorkflowNode)
+}
+
+// runDraftWorkflow 调试运行工作流草稿，总是以 SSE 返回
+func (s *Server) runDraftWorkflow(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		Inputs map[string]interface{} `json:"inputs"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	appID := params["app_id"]
+	s.mu.Lock()
+	_, ok := s.apps[appID]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "app_not_found", "App not found.")
+		return
+	}
+
+	start := time.Now()
+	outputs, err := s.workflow(appID, req.Inputs)
+
+	s.mu.Lock()
+	run := &workflowRun{
+		ID:          s.nextID(),
+		WorkflowID:  "draft-" + appID,
+		Status:      "succeeded",
+		Inputs:      req.Inputs,
+		Outputs:     outputs,
+		TotalSteps:  3,
+		TotalTokens: 42,
+		ElapsedTime: time.Since(start).Seconds(),
+		CreatedAt:   start.Unix(),
+		FinishedAt:  time.Now().Unix(),
+		appID:       appID,
+	}
+	if err != nil {
+		run.Status = "failed"
+		run.Error = err.Error()
+		run.Outputs = nil
+	}
+	s.workflowRuns[run.ID] = run
+	taskID := s.nextID()
+	delay := s.streamDelay
+	s.mu.Unlock()
+
+	s.streamWorkflowRun(w, r, run, taskID, delay)
+}
+
+// runDraftWorkflowNode 调试运行单个节点，以 JSON 返回节点执行结果
+// 节点ID以 iteration 开头时模拟迭代节点，以 SSE 返回节点事件
+func (s *Server) runDraftWorkflowNode(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		Inputs map[string]interface{} `json:"inputs"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	appID, nodeID := params["app_id"], params["node_id"]
+	s.mu.Lock()
+	_, ok := s.apps[appID]
+	id := s.nextID()
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "app_not_found", "App not found.")
+		return
+	}
+
+	start := time.Now()
+	outputs, err := s.workflow(appID, req.Inputs)
+	nodeType := nodeID
+	if strings.HasPrefix(nodeID, "iteration") {
+		nodeType = "iteration"
+	}
+	result := map[string]interface{}{
+		"id":           id,
+		"node_id":      nodeID,
+		"node_type":    nodeType,
+		"title":        nodeID,
+		"index":        1,
+		"inputs":       req.Inputs,
+		"outputs":      outputs,
+		"status":       "succeeded",
+		"elapsed_time": time.Since(start).Seconds(),
+		"created_at":   start.Unix(),
+		"finished_at":  time.Now().Unix(),
+	}
+	if err != nil {
+		result["status"] = "failed"
+		result["error"] = err.Error()
+		result["outputs"] = nil
+	}
+	if nodeType != "iteration" {
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	started := map[string]interface{}{
+		"id": id, "node_id": nodeID, "node_type": nodeType, "title": nodeID, "index": 1, "inputs": req.Inputs, "created_at": start.Unix(),
+	}
+	for _, event := range []map[string]interface{}{
+		{"event": "node_started", "data": started},
+		{"event": "node_finished", "data": result},
+	} {
+		payload, _ := json.Marshal(event)
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+	}
+}
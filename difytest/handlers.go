@@ -0,0 +1,860 @@
+package difytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type apiKey struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Token      string `json:"token"`
+	LastUsedAt *int64 `json:"last_used_at"`
+	CreatedAt  int64  `json:"created_at"`
+
+	appID string
+}
+
+type dataset struct {
+	ID                     string                 `json:"id"`
+	Name                   string                 `json:"name"`
+	Description            string                 `json:"description"`
+	Provider               string                 `json:"provider"`
+	Permission             string                 `json:"permission"`
+	DataSourceType         string                 `json:"data_source_type"`
+	IndexingTechnique      string                 `json:"indexing_technique"`
+	AppCount               int                    `json:"app_count"`
+	DocumentCount          int                    `json:"document_count"`
+	WordCount              int                    `json:"word_count"`
+	CreatedBy              string                 `json:"created_by"`
+	CreatedAt              int64                  `json:"created_at"`
+	UpdatedBy              string                 `json:"updated_by"`
+	UpdatedAt              int64                  `json:"updated_at"`
+	EmbeddingModel         string                 `json:"embedding_model"`
+	EmbeddingModelProvider string                 `json:"embedding_model_provider"`
+	EmbeddingAvailable     bool                   `json:"embedding_available"`
+	RetrievalModel         map[string]interface{} `json:"retrieval_model_dict"`
+	Tags                   []interface{}          `json:"tags"`
+
+	documents       []*Document
+	tagIDs          []string
+	queries         []*hitTestingQuery
+	metadata        []*metadataField
+	builtInMetadata bool // 是否启用内置元数据字段
+}
+
+// Document 模拟服务中保存的文档
+type Document struct {
+	ID             string                 `json:"id"`
+	Position       int                    `json:"position"`
+	DataSourceType string                 `json:"data_source_type"`
+	Name           string                 `json:"name"`
+	CreatedFrom    string                 `json:"created_from"`
+	CreatedAt      int64                  `json:"created_at"`
+	Tokens         int64                  `json:"tokens"`
+	IndexingStatus string                 `json:"indexing_status"`
+	Enabled        bool                   `json:"enabled"`
+	Archived       bool                   `json:"archived"`
+	DisplayStatus  string                 `json:"display_status"`
+	WordCount      int64                  `json:"word_count"`
+	DocForm        string                 `json:"doc_form"`
+	Batch          string                 `json:"-"`
+	Content        []byte                 `json:"-"` // 上传的文件内容
+	ContentType    string                 `json:"-"` // 上传文件的 MIME 类型
+	Data           map[string]interface{} `json:"-"` // 创建文档时提交的 data 参数
+	Metadata       map[string]interface{} `json:"-"` // 自定义元数据：字段名 -> 值
+}
+
+type app struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	Mode           string          `json:"mode"`
+	Icon           string          `json:"icon"`
+	IconBackground string          `json:"icon_background"`
+	EnableSite     bool            `json:"enable_site"`
+	EnableAPI      bool            `json:"enable_api"`
+	ModelConfig    json.RawMessage `json:"model_config"`
+	CreatedBy      string          `json:"created_by"`
+	CreatedAt      int64           `json:"created_at"`
+	UpdatedBy      string          `json:"updated_by"`
+	UpdatedAt      int64           `json:"updated_at"`
+
+	annotations     []*annotation
+	annotationReply map[string]interface{} // 标注回复配置，为 nil 时未开启
+}
+
+type workflowRun struct {
+	ID          string                 `json:"id"`
+	WorkflowID  string                 `json:"workflow_id"`
+	Status      string                 `json:"status"`
+	Inputs      map[string]interface{} `json:"inputs"`
+	Outputs     map[string]interface{} `json:"outputs"`
+	Error       string                 `json:"error"`
+	TotalSteps  int64                  `json:"total_steps"`
+	TotalTokens int64                  `json:"total_tokens"`
+	ElapsedTime float64                `json:"elapsed_time"`
+	CreatedAt   int64                  `json:"created_at"`
+	FinishedAt  int64                  `json:"finished_at"`
+
+	appID string
+}
+
+// Documents 返回知识库中的文档，按创建顺序排列
+func (s *Server) Documents(datasetID string) []Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil
+	}
+	documents := make([]Document, 0, len(ds.documents))
+	for _, doc := range ds.documents {
+		documents = append(documents, *doc)
+	}
+	return documents
+}
+
+// ModelConfig 返回应用最近一次保存的模型配置
+func (s *Server) ModelConfig(appID string) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.apps[appID]; ok {
+		return a.ModelConfig
+	}
+	return nil
+}
+
+func (s *Server) registerRoutes() {
+	// console
+	s.handle(http.MethodPost, "/console/api/login", authNone, s.login)
+	s.handle(http.MethodPost, "/console/api/refresh-token", authNone, s.refreshToken)
+	s.handle(http.MethodGet, "/console/api/datasets/api-keys", authConsole, s.listDatasetAPIKeys)
+	s.handle(http.MethodPost, "/console/api/datasets/api-keys", authConsole, s.createDatasetAPIKey)
+	s.handle(http.MethodPost, "/console/api/apps", authConsole, s.createApp)
+	s.handle(http.MethodPost, "/console/api/apps/{app_id}/model-config", authConsole, s.updateModelConfig)
+	s.handle(http.MethodPost, "/console/api/apps/{app_id}/api-keys", authConsole, s.createAppAPIKey)
+	s.handle(http.MethodGet, "/console/api/files/upload", authConsole, s.uploadConfig)
+
+	// datasets
+	s.handle(http.MethodPost, "/v1/datasets", authDataset, s.createDataset)
+	s.handle(http.MethodGet, "/v1/datasets", authDataset, s.listDatasets)
+	s.handle(http.MethodPost, "/v1/datasets/{dataset_id}/document/create-by-file", authDataset, s.createDocumentByFile)
+	s.handle(http.MethodGet, "/v1/datasets/{dataset_id}/documents", authDataset, s.listDocuments)
+	s.handle(http.MethodGet, "/v1/datasets/{dataset_id}/documents/{batch}/indexing-status", authDataset, s.indexingStatus)
+	s.handle(http.MethodDelete, "/v1/datasets/{dataset_id}/documents/{document_id}", authDataset, s.deleteDocument)
+	s.handle(http.MethodPost, "/v1/datasets/{dataset_id}/documents/{document_id}/update-by-file", authDataset, s.updateDocumentByFile)
+	s.handle(http.MethodPatch, "/v1/datasets/{dataset_id}/documents/status/{action}", authDataset, s.updateDocumentsStatus)
+
+	// app
+	s.handle(http.MethodPost, "/v1/workflows/run", authApp, s.runWorkflow)
+	s.handle(http.MethodPost, "/v1/workflows/{workflow_id}/run", authApp, s.runWorkflow)
+	s.handle(http.MethodGet, "/v1/workflows/run/{workflow_run_id}", authApp, s.getWorkflowRun)
+	s.handle(http.MethodGet, "/v1/workflows/logs", authApp, s.listWorkflowLogs)
+	s.registerAnnotationRoutes()
+	s.registerTagRoutes()
+	s.registerDataSourceRoutes()
+	s.registerIndexingEstimateRoutes()
+	s.registerDraftWorkflowRoutes()
+	s.registerRetrievalRoutes()
+	s.registerMetadataRoutes()
+	s.registerAudioRoutes()
+}
+
+func (s *Server) issueTokens() map[string]interface{} {
+	accessToken := randomToken("access-")
+	refreshToken := randomToken("refresh-")
+	s.accessTokens[accessToken] = true
+	s.refreshTokens[refreshToken] = true
+	return map[string]interface{}{
+		"result": "success",
+		"data": map[string]string{
+			"access_token":  accessToken,
+			"refresh_token": refreshToken,
+		},
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.Email != s.email || req.Password != s.password {
+		writeError(w, http.StatusUnauthorized, "authentication_failed", "Invalid email or password.")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.issueTokens())
+}
+
+func (s *Server) refreshToken(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.refreshTokens[req.RefreshToken] {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Invalid refresh token")
+		return
+	}
+	delete(s.refreshTokens, req.RefreshToken)
+	writeJSON(w, http.StatusOK, s.issueTokens())
+}
+
+func (s *Server) listDatasetAPIKeys(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := s.datasetAPIKeys
+	if keys == nil {
+		keys = []*apiKey{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": keys})
+}
+
+func (s *Server) createDatasetAPIKey(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := &apiKey{ID: s.nextID(), Type: "dataset", Token: randomToken("dataset-"), CreatedAt: time.Now().Unix()}
+	s.datasetAPIKeys = append(s.datasetAPIKeys, key)
+	writeJSON(w, http.StatusOK, key)
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var a app
+	if !decodeJSON(w, r, &a) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().Unix()
+	a.ID = s.nextID()
+	a.EnableSite = true
+	a.EnableAPI = true
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	s.apps[a.ID] = &a
+	writeJSON(w, http.StatusCreated, &a)
+}
+
+func (s *Server) updateModelConfig(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(body) {
+		writeError(w, http.StatusBadRequest, "invalid_param", "invalid model config")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.apps[params["app_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "app_not_found", "App not found.")
+		return
+	}
+	a.ModelConfig = body
+	a.UpdatedAt = time.Now().Unix()
+	writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+func (s *Server) createAppAPIKey(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.apps[params["app_id"]]; !ok {
+		writeError(w, http.StatusNotFound, "app_not_found", "App not found.")
+		return
+	}
+	key := &apiKey{ID: s.nextID(), Type: "app", Token: randomToken("app-"), CreatedAt: time.Now().Unix(), appID: params["app_id"]}
+	s.appAPIKeys[key.Token] = key
+	writeJSON(w, http.StatusCreated, key)
+}
+
+func (s *Server) createDataset(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		Name                   string                 `json:"name"`
+		Description            string                 `json:"description"`
+		IndexingTechnique      string                 `json:"indexing_technique"`
+		Permission             string                 `json:"permission"`
+		Provider               string                 `json:"provider"`
+		EmbeddingModel         string                 `json:"embedding_model"`
+		EmbeddingModelProvider string                 `json:"embedding_model_provider"`
+		RetrievalModel         map[string]interface{} `json:"retrieval_model"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "invalid_param", "Name is required.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ds := range s.datasets {
+		if ds.Name == req.Name {
+			writeError(w, http.StatusConflict, "dataset_name_duplicate", "The dataset name already exists. Please modify your dataset name.")
+			return
+		}
+	}
+	now := time.Now().Unix()
+	ds := &dataset{
+		ID:                     s.nextID(),
+		Name:                   req.Name,
+		Description:            req.Description,
+		Provider:               req.Provider,
+		Permission:             req.Permission,
+		IndexingTechnique:      req.IndexingTechnique,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+		EmbeddingModel:         req.EmbeddingModel,
+		EmbeddingModelProvider: req.EmbeddingModelProvider,
+		EmbeddingAvailable:     true,
+		RetrievalModel:         req.RetrievalModel,
+		Tags:                   []interface{}{},
+	}
+	if ds.Provider == "" {
+		ds.Provider = "vendor"
+	}
+	if ds.Permission == "" {
+		ds.Permission = "only_me"
+	}
+	s.datasets[ds.ID] = ds
+	writeJSON(w, http.StatusOK, ds)
+}
+
+func (s *Server) listDatasets(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keyword := r.URL.Query().Get("keyword")
+	tagIDs := r.URL.Query()["tag_ids"]
+	var all []*dataset
+	for _, ds := range s.datasets {
+		if keyword != "" && !strings.Contains(ds.Name, keyword) {
+			continue
+		}
+		matched := true
+		for _, id := range tagIDs {
+			if !containsString(ds.tagIDs, id) {
+				matched = false
+			}
+		}
+		if !matched {
+			continue
+		}
+		ds.Tags = ds.Tags[:0]
+		for _, t := range s.datasetTags(ds) {
+			ds.Tags = append(ds.Tags, t)
+		}
+		all = append(all, ds)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	page, limit := pagination(r)
+	data, hasMore := paginate(all, page, limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":     data,
+		"has_more": hasMore,
+		"limit":    limit,
+		"total":    len(all),
+		"page":     page,
+	})
+}
+
+func (s *Server) createDocumentByFile(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "no_file_uploaded", "Please upload your file.")
+		return
+	}
+	defer file.Close()
+	content, ok := s.readUploadedFile(w, file)
+	if !ok {
+		return
+	}
+	data := map[string]interface{}{}
+	if raw := r.FormValue("data"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_param", "data is not valid JSON")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	docForm, _ := data["doc_form"].(string)
+	if docForm == "" {
+		docForm = "text_model"
+	}
+	doc := &Document{
+		ID:             s.nextID(),
+		Position:       len(ds.documents) + 1,
+		DataSourceType: "upload_file",
+		Name:           header.Filename,
+		CreatedFrom:    "api",
+		CreatedAt:      time.Now().Unix(),
+		Tokens:         int64(len(content) / 4),
+		IndexingStatus: "completed",
+		Enabled:        true,
+		DisplayStatus:  "available",
+		WordCount:      int64(len(content)),
+		DocForm:        docForm,
+		Batch:          fmt.Sprintf("%014d", s.seq),
+		Content:        content,
+		ContentType:    header.Header.Get("Content-Type"),
+		Data:           data,
+	}
+	ds.documents = append(ds.documents, doc)
+	ds.DocumentCount = len(ds.documents)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"document": doc,
+		"batch":    doc.Batch,
+	})
+}
+
+// readUploadedFile 读取上传的文件，超过大小限制时返回 413
+func (s *Server) readUploadedFile(w http.ResponseWriter, file io.Reader) ([]byte, bool) {
+	s.mu.Lock()
+	limit := int64(s.fileSizeLimit) << 20
+	s.mu.Unlock()
+	content, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return nil, false
+	}
+	if int64(len(content)) > limit {
+		writeError(w, http.StatusRequestEntityTooLarge, "file_too_large", fmt.Sprintf("File size exceeded. %d MB", s.fileSizeLimit))
+		return nil, false
+	}
+	return content, true
+}
+
+func (s *Server) uploadConfig(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"file_size_limit":            s.fileSizeLimit,
+		"batch_count_limit":          5,
+		"image_file_size_limit":      10,
+		"video_file_size_limit":      100,
+		"audio_file_size_limit":      50,
+		"workflow_file_upload_limit": 10,
+	})
+}
+
+func (s *Server) updateDocumentByFile(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "no_file_uploaded", "Please upload your file.")
+		return
+	}
+	defer file.Close()
+	content, ok := s.readUploadedFile(w, file)
+	if !ok {
+		return
+	}
+	data := map[string]interface{}{}
+	if raw := r.FormValue("data"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_param", "data is not valid JSON")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	for _, doc := range ds.documents {
+		if doc.ID != params["document_id"] {
+			continue
+		}
+		if name, _ := data["name"].(string); name != "" {
+			doc.Name = name
+		}
+		s.seq++
+		doc.Batch = fmt.Sprintf("%014d", s.seq)
+		doc.Content = content
+		doc.ContentType = header.Header.Get("Content-Type")
+		doc.Data = data
+		doc.Tokens = int64(len(content) / 4)
+		doc.WordCount = int64(len(content))
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"document": doc,
+			"batch":    doc.Batch,
+		})
+		return
+	}
+	writeError(w, http.StatusNotFound, "document_not_found", "Document not found.")
+}
+
+func (s *Server) updateDocumentsStatus(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		DocumentIDs []string `json:"document_ids"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	for _, id := range req.DocumentIDs {
+		for _, doc := range ds.documents {
+			if doc.ID != id {
+				continue
+			}
+			switch params["action"] {
+			case "enable":
+				doc.Enabled = true
+			case "disable":
+				doc.Enabled = false
+			case "archive":
+				doc.Archived = true
+			case "un_archive":
+				doc.Archived = false
+			default:
+				writeError(w, http.StatusBadRequest, "invalid_action", "Invalid action.")
+				return
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"result": "success"})
+}
+
+func (s *Server) listDocuments(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	keyword := r.URL.Query().Get("keyword")
+	var all []*Document
+	for _, doc := range ds.documents {
+		if keyword == "" || strings.Contains(doc.Name, keyword) {
+			all = append(all, doc)
+		}
+	}
+	page, limit := pagination(r)
+	data, hasMore := paginate(all, page, limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":     data,
+		"has_more": hasMore,
+		"limit":    limit,
+		"total":    len(all),
+		"page":     page,
+	})
+}
+
+func (s *Server) indexingStatus(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	data := []map[string]interface{}{}
+	for _, doc := range ds.documents {
+		if doc.Batch != params["batch"] {
+			continue
+		}
+		data = append(data, map[string]interface{}{
+			"id":                    doc.ID,
+			"indexing_status":       doc.IndexingStatus,
+			"processing_started_at": doc.CreatedAt,
+			"completed_at":          doc.CreatedAt,
+			"completed_segments":    1,
+			"total_segments":        1,
+		})
+	}
+	if len(data) == 0 {
+		writeError(w, http.StatusNotFound, "document_not_found", "Documents not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+func (s *Server) deleteDocument(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	for i, doc := range ds.documents {
+		if doc.ID == params["document_id"] {
+			ds.documents = append(ds.documents[:i], ds.documents[i+1:]...)
+			ds.DocumentCount = len(ds.documents)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "document_not_found", "Document not found.")
+}
+
+func (s *Server) runWorkflow(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		Inputs       map[string]interface{} `json:"inputs"`
+		ResponseMode string                 `json:"response_mode"`
+		User         string                 `json:"user"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.User == "" {
+		writeError(w, http.StatusBadRequest, "invalid_param", "user is required")
+		return
+	}
+	appID := s.appIDByToken(r)
+
+	start := time.Now()
+	outputs, err := s.workflow(appID, req.Inputs)
+
+	s.mu.Lock()
+	workflowID := params["workflow_id"]
+	if workflowID == "" {
+		workflowID = "published-" + appID
+	}
+	run := &workflowRun{
+		ID:          s.nextID(),
+		WorkflowID:  workflowID,
+		Status:      "succeeded",
+		Inputs:      req.Inputs,
+		Outputs:     outputs,
+		TotalSteps:  3,
+		TotalTokens: 42,
+		ElapsedTime: time.Since(start).Seconds(),
+		CreatedAt:   start.Unix(),
+		FinishedAt:  time.Now().Unix(),
+		appID:       appID,
+	}
+	if err != nil {
+		run.Status = "failed"
+		run.Error = err.Error()
+		run.Outputs = nil
+	}
+	s.workflowRuns[run.ID] = run
+	taskID := s.nextID()
+	delay := s.streamDelay
+	s.mu.Unlock()
+
+	if req.ResponseMode != "streaming" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"workflow_run_id": run.ID,
+			"task_id":         taskID,
+			"data":            run,
+		})
+		return
+	}
+
+	s.streamWorkflowRun(w, r, run, taskID, delay)
+}
+
+// streamWorkflowRun 以 SSE 返回工作流执行过程，依次发送开始、节点、文本片段和结束事件
+func (s *Server) streamWorkflowRun(w http.ResponseWriter, r *http.Request, run *workflowRun, taskID string, delay time.Duration) {
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	send := func(event string, data interface{}) bool {
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return false
+			}
+		}
+		payload, _ := json.Marshal(map[string]interface{}{
+			"event":           event,
+			"workflow_run_id": run.ID,
+			"task_id":         taskID,
+			"data":            data,
+		})
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+
+	if !send("workflow_started", map[string]interface{}{
+		"id": run.ID, "workflow_id": run.WorkflowID, "sequence_number": 1, "inputs": run.Inputs, "created_at": run.CreatedAt,
+	}) {
+		return
+	}
+	for i, node := range []string{"start", "llm"} {
+		nodeData := map[string]interface{}{
+			"id": s.idFor(run.ID, node), "node_id": node, "node_type": node, "title": node, "index": i + 1, "created_at": run.CreatedAt,
+		}
+		if !send("node_started", nodeData) {
+			return
+		}
+		nodeData["status"] = "succeeded"
+		if !send("node_finished", nodeData) {
+			return
+		}
+	}
+	if text, ok := run.Outputs["text"].(string); ok {
+		for _, chunk := range strings.SplitAfter(text, " ") {
+			if !send("text_chunk", map[string]interface{}{"text": chunk, "from_variable_selector": []string{"llm", "text"}}) {
+				return
+			}
+		}
+	}
+	send("workflow_finished", run)
+}
+
+func (s *Server) getWorkflowRun(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	appID := s.appIDByToken(r)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.workflowRuns[params["workflow_run_id"]]
+	if !ok || run.appID != appID {
+		writeError(w, http.StatusNotFound, "not_found", "Workflow run not found.")
+		return
+	}
+	// 与 Dify 一致，详情接口中的 inputs 和 outputs 是 JSON 字符串
+	inputs, _ := json.Marshal(run.Inputs)
+	outputs, _ := json.Marshal(run.Outputs)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":           run.ID,
+		"workflow_id":  run.WorkflowID,
+		"status":       run.Status,
+		"inputs":       string(inputs),
+		"outputs":      string(outputs),
+		"error":        run.Error,
+		"total_steps":  run.TotalSteps,
+		"total_tokens": run.TotalTokens,
+		"elapsed_time": run.ElapsedTime,
+		"created_at":   run.CreatedAt,
+		"finished_at":  run.FinishedAt,
+	})
+}
+
+func (s *Server) listWorkflowLogs(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	appID := s.appIDByToken(r)
+	query := r.URL.Query()
+	var after, before time.Time
+	for name, t := range map[string]*time.Time{"created_at__after": &after, "created_at__before": &before} {
+		if v := query.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_param", name+" must be ISO 8601")
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var runs []*workflowRun
+	for _, run := range s.workflowRuns {
+		created := time.Unix(run.CreatedAt, 0)
+		switch {
+		case run.appID != appID:
+		case query.Get("status") != "" && run.Status != query.Get("status"):
+		case !after.IsZero() && created.Before(after):
+		case !before.IsZero() && created.After(before):
+		default:
+			runs = append(runs, run)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].CreatedAt != runs[j].CreatedAt {
+			return runs[i].CreatedAt > runs[j].CreatedAt
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	logs := make([]map[string]interface{}, 0, len(runs))
+	for _, run := range runs {
+		logs = append(logs, map[string]interface{}{
+			"id": "log-" + run.ID,
+			"workflow_run": map[string]interface{}{
+				"id":           run.ID,
+				"version":      "draft",
+				"status":       run.Status,
+				"error":        run.Error,
+				"elapsed_time": run.ElapsedTime,
+				"total_tokens": run.TotalTokens,
+				"total_steps":  run.TotalSteps,
+				"created_at":   run.CreatedAt,
+				"finished_at":  run.FinishedAt,
+			},
+			"created_from":    "service-api",
+			"created_by_role": "end_user",
+			"created_at":      run.CreatedAt,
+		})
+	}
+	page, limit := pagination(r)
+	data, hasMore := paginate(logs, page, limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":     data,
+		"has_more": hasMore,
+		"limit":    limit,
+		"total":    len(logs),
+		"page":     page,
+	})
+}
+
+// idFor 为工作流中的节点执行生成稳定的ID
+func (s *Server) idFor(runID, node string) string {
+	return runID[:8] + "-" + node
+}
+
+func pagination(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func paginate[T any](all []T, page, limit int) ([]T, bool) {
+	start := (page - 1) * limit
+	if start >= len(all) {
+		return []T{}, false
+	}
+	end := start + limit
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[start:end], end < len(all)
+}
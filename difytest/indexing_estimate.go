@@ -0,0 +1,140 @@
+package difytest
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// uploadedFile 通过控制台上传的文件
+type uploadedFile struct {
+	ID      string
+	Name    string
+	Content []byte
+}
+
+func (s *Server) registerIndexingEstimateRoutes() {
+	s.handle(http.MethodPost, "/console/api/files/upload", authConsole, s.uploadConsoleFile)
+	s.handle(http.MethodPost, "/console/api/datasets/indexing-estimate", authConsole, s.indexingEstimate)
+}
+
+func (s *Server) uploadConsoleFile(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "no_file_uploaded", "Please upload your file.")
+		return
+	}
+	defer file.Close()
+	content, ok := s.readUploadedFile(w, file)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := &uploadedFile{ID: s.nextID(), Name: header.Filename, Content: content}
+	s.uploadedFiles[f.ID] = f
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":         f.ID,
+		"name":       f.Name,
+		"size":       len(content),
+		"extension":  strings.TrimPrefix(path.Ext(f.Name), "."),
+		"mime_type":  header.Header.Get("Content-Type"),
+		"created_by": "difytest",
+		"created_at": time.Now().Unix(),
+	})
+}
+
+// indexingEstimate 按分段规则切分已上传的文件，自动模式以空行分段
+// 每 4 个字节计为 1 个 token，费用为每千 token 0.0001 USD，与 Dify 一样以十进制字符串返回
+func (s *Server) indexingEstimate(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		InfoList struct {
+			FileInfoList struct {
+				FileIDs []string `json:"file_ids"`
+			} `json:"file_info_list"`
+		} `json:"info_list"`
+		ProcessRule struct {
+			Mode  string `json:"mode"`
+			Rules struct {
+				Segmentation struct {
+					Separator string `json:"separator"`
+				} `json:"segmentation"`
+				SubchunkSegmentation *struct {
+					Separator string `json:"separator"`
+				} `json:"subchunk_segmentation"`
+			} `json:"rules"`
+		} `json:"process_rule"`
+		DocForm string `json:"doc_form"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	var content []byte
+	for _, id := range req.InfoList.FileInfoList.FileIDs {
+		f, ok := s.uploadedFiles[id]
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusNotFound, "file_not_found", "File not found.")
+			return
+		}
+		content = append(content, f.Content...)
+	}
+	s.mu.Unlock()
+
+	separator := "\n\n"
+	if req.ProcessRule.Mode != "automatic" && req.ProcessRule.Rules.Segmentation.Separator != "" {
+		separator = unescapeSeparator(req.ProcessRule.Rules.Segmentation.Separator)
+	}
+	var childSeparator string
+	if sub := req.ProcessRule.Rules.SubchunkSegmentation; sub != nil {
+		childSeparator = unescapeSeparator(sub.Separator)
+	}
+
+	preview := []map[string]interface{}{}
+	qaPreview := []map[string]string{}
+	total := 0
+	for _, segment := range strings.Split(string(content), separator) {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+		total++
+		if len(preview)+len(qaPreview) >= 10 {
+			continue
+		}
+		if req.DocForm == "qa_model" {
+			qaPreview = append(qaPreview, map[string]string{"question": segment + "?", "answer": segment})
+			continue
+		}
+		children := []string{}
+		if childSeparator != "" {
+			for _, child := range strings.Split(segment, childSeparator) {
+				if child = strings.TrimSpace(child); child != "" {
+					children = append(children, child)
+				}
+			}
+		}
+		preview = append(preview, map[string]interface{}{"content": segment, "child_chunks": children})
+	}
+	tokens := len(content) / 4
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_segments": total,
+		"tokens":         tokens,
+		"total_price":    fmt.Sprintf("%.7f", float64(tokens)/1000*0.0001),
+		"currency":       "USD",
+		"preview":        preview,
+		"qa_preview":     qaPreview,
+	})
+}
+
+// unescapeSeparator 与 Dify 一致，分隔符中的 \n 以转义形式提交
+func unescapeSeparator(separator string) string {
+	return strings.ReplaceAll(separator, `\n`, "\n")
+}
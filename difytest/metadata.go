@@ -0,0 +1,233 @@
+package difytest
+
+import (
+	"net/http"
+)
+
+// metadataField 知识库的自定义元数据字段
+type metadataField struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// builtInMetadataFields 内置元数据字段名称，不能用作自定义字段名
+var builtInMetadataFields = []string{"document_name", "uploader", "upload_date", "last_update_date", "source"}
+
+func (s *Server) registerMetadataRoutes() {
+	s.handle(http.MethodPost, "/v1/datasets/{dataset_id}/metadata", authDataset, s.createMetadataField)
+	s.handle(http.MethodGet, "/v1/datasets/{dataset_id}/metadata", authDataset, s.listMetadataFields)
+	s.handle(http.MethodPatch, "/v1/datasets/{dataset_id}/metadata/{metadata_id}", authDataset, s.updateMetadataField)
+	s.handle(http.MethodDelete, "/v1/datasets/{dataset_id}/metadata/{metadata_id}", authDataset, s.deleteMetadataField)
+	s.handle(http.MethodPost, "/v1/datasets/{dataset_id}/metadata/built-in/{action}", authDataset, s.toggleBuiltInMetadataFields)
+	s.handle(http.MethodPost, "/v1/datasets/{dataset_id}/documents/metadata", authDataset, s.updateDocumentsMetadata)
+}
+
+// metadataDataset 返回请求对应的知识库，不存在时写入 404，调用方需持有锁
+func (s *Server) metadataDataset(w http.ResponseWriter, params map[string]string) (*dataset, bool) {
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+	}
+	return ds, ok
+}
+
+// findMetadataField 按ID查找字段，调用方需持有锁
+func (ds *dataset) findMetadataField(id string) *metadataField {
+	for _, field := range ds.metadata {
+		if field.ID == id {
+			return field
+		}
+	}
+	return nil
+}
+
+// validMetadataName 字段名不能为空、不能与内置字段或其他字段重名，调用方需持有锁
+func (ds *dataset) validMetadataName(w http.ResponseWriter, name, exceptID string) bool {
+	if name == "" || len(name) > 255 {
+		writeError(w, http.StatusBadRequest, "invalid_param", "Metadata name must be between 1 to 255 characters.")
+		return false
+	}
+	if containsString(builtInMetadataFields, name) {
+		writeError(w, http.StatusBadRequest, "invalid_param", "Metadata name already exists in built-in fields.")
+		return false
+	}
+	for _, field := range ds.metadata {
+		if field.Name == name && field.ID != exceptID {
+			writeError(w, http.StatusBadRequest, "invalid_param", "Metadata name already exists.")
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) createMetadataField(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Type != "string" && req.Type != "number" && req.Type != "time" {
+		writeError(w, http.StatusBadRequest, "invalid_param", "Metadata type must be string, number or time.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.metadataDataset(w, params)
+	if !ok || !ds.validMetadataName(w, req.Name, "") {
+		return
+	}
+	field := &metadataField{ID: s.nextID(), Name: req.Name, Type: req.Type}
+	ds.metadata = append(ds.metadata, field)
+	writeJSON(w, http.StatusCreated, field)
+}
+
+func (s *Server) listMetadataFields(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.metadataDataset(w, params)
+	if !ok {
+		return
+	}
+	fields := make([]map[string]interface{}, 0, len(ds.metadata))
+	for _, field := range ds.metadata {
+		count := 0
+		for _, doc := range ds.documents {
+			if _, ok := doc.Metadata[field.Name]; ok {
+				count++
+			}
+		}
+		fields = append(fields, map[string]interface{}{"id": field.ID, "name": field.Name, "type": field.Type, "count": count})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"doc_metadata":           fields,
+		"built_in_field_enabled": ds.builtInMetadata,
+	})
+}
+
+// updateMetadataField 重命名字段，文档中已有的值随之改名
+func (s *Server) updateMetadataField(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.metadataDataset(w, params)
+	if !ok {
+		return
+	}
+	field := ds.findMetadataField(params["metadata_id"])
+	if field == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Metadata not found.")
+		return
+	}
+	if !ds.validMetadataName(w, req.Name, field.ID) {
+		return
+	}
+	for _, doc := range ds.documents {
+		if value, ok := doc.Metadata[field.Name]; ok {
+			delete(doc.Metadata, field.Name)
+			doc.Metadata[req.Name] = value
+		}
+	}
+	field.Name = req.Name
+	writeJSON(w, http.StatusOK, field)
+}
+
+// deleteMetadataField 删除字段及文档中的值，与 Dify 一样返回 204
+func (s *Server) deleteMetadataField(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.metadataDataset(w, params)
+	if !ok {
+		return
+	}
+	field := ds.findMetadataField(params["metadata_id"])
+	if field == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Metadata not found.")
+		return
+	}
+	for i, f := range ds.metadata {
+		if f == field {
+			ds.metadata = append(ds.metadata[:i], ds.metadata[i+1:]...)
+			break
+		}
+	}
+	for _, doc := range ds.documents {
+		delete(doc.Metadata, field.Name)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) toggleBuiltInMetadataFields(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.metadataDataset(w, params)
+	if !ok {
+		return
+	}
+	switch params["action"] {
+	case "enable":
+		ds.builtInMetadata = true
+	case "disable":
+		ds.builtInMetadata = false
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_param", "Action must be enable or disable.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+// updateDocumentsMetadata 整体替换文档的自定义元数据，先校验全部文档和字段再修改
+func (s *Server) updateDocumentsMetadata(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req struct {
+		OperationData []struct {
+			DocumentID   string `json:"document_id"`
+			MetadataList []struct {
+				ID    string      `json:"id"`
+				Name  string      `json:"name"`
+				Value interface{} `json:"value"`
+			} `json:"metadata_list"`
+		} `json:"operation_data"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.metadataDataset(w, params)
+	if !ok {
+		return
+	}
+	docs := make([]*Document, len(req.OperationData))
+	for i, op := range req.OperationData {
+		for _, doc := range ds.documents {
+			if doc.ID == op.DocumentID {
+				docs[i] = doc
+			}
+		}
+		if docs[i] == nil {
+			writeError(w, http.StatusNotFound, "document_not_found", "Document not found.")
+			return
+		}
+		for _, value := range op.MetadataList {
+			if field := ds.findMetadataField(value.ID); field == nil || field.Name != value.Name {
+				writeError(w, http.StatusBadRequest, "invalid_param", "Metadata not found.")
+				return
+			}
+		}
+	}
+	for i, op := range req.OperationData {
+		metadata := map[string]interface{}{}
+		for _, value := range op.MetadataList {
+			metadata[value.Name] = value.Value
+		}
+		docs[i].Metadata = metadata
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
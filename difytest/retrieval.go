@@ -0,0 +1,176 @@
+package difytest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// hitTestingQuery 召回测试历史
+type hitTestingQuery struct {
+	ID            string `json:"id"`
+	Content       string `json:"content"`
+	Source        string `json:"source"`
+	SourceAppID   string `json:"source_app_id"`
+	CreatedByRole string `json:"created_by_role"`
+	CreatedBy     string `json:"created_by"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+// retrievalRequest 检索与召回测试的请求体
+type retrievalRequest struct {
+	Query          string `json:"query"`
+	RetrievalModel *struct {
+		SearchMethod          string  `json:"search_method"`
+		TopK                  int     `json:"top_k"`
+		ScoreThresholdEnabled bool    `json:"score_threshold_enabled"`
+		ScoreThreshold        float64 `json:"score_threshold"`
+	} `json:"retrieval_model"`
+}
+
+func (s *Server) registerRetrievalRoutes() {
+	s.handle(http.MethodPost, "/v1/datasets/{dataset_id}/retrieve", authDataset, s.retrieve)
+	s.handle(http.MethodPost, "/console/api/datasets/{dataset_id}/hit-testing", authConsole, s.hitTesting)
+	s.handle(http.MethodGet, "/console/api/datasets/{dataset_id}/queries", authConsole, s.listHitTestingQueries)
+}
+
+func (s *Server) retrieve(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.search(w, r, params, false)
+}
+
+func (s *Server) hitTesting(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.search(w, r, params, true)
+}
+
+// search 在知识库的分段中检索，分段按空行切分，分数为查询词在分段中出现的比例（忽略大小写），检索方式不影响打分
+// 默认返回前 4 条，record 为 true 时把查询记录到召回测试历史
+func (s *Server) search(w http.ResponseWriter, r *http.Request, params map[string]string, record bool) {
+	var req retrievalRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_param", "Query is required.")
+		return
+	}
+	topK, threshold := 4, 0.0
+	if m := req.RetrievalModel; m != nil {
+		if m.TopK > 0 {
+			topK = m.TopK
+		}
+		if m.ScoreThresholdEnabled {
+			threshold = m.ScoreThreshold
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	terms := strings.Fields(strings.ToLower(req.Query))
+	type hit struct {
+		score  float64
+		record map[string]interface{}
+	}
+	var hits []hit
+	for _, doc := range ds.documents {
+		if !doc.Enabled || doc.Archived {
+			continue
+		}
+		position := 0
+		for _, content := range strings.Split(string(doc.Content), "\n\n") {
+			content = strings.TrimSpace(content)
+			if content == "" {
+				continue
+			}
+			position++
+			matched := 0
+			for _, term := range terms {
+				if strings.Contains(strings.ToLower(content), term) {
+					matched++
+				}
+			}
+			score := float64(matched) / float64(len(terms))
+			if matched == 0 || score < threshold {
+				continue
+			}
+			hits = append(hits, hit{score: score, record: map[string]interface{}{
+				"segment": map[string]interface{}{
+					"id":          fmt.Sprintf("%s-%04d", doc.ID, position),
+					"position":    position,
+					"document_id": doc.ID,
+					"content":     content,
+					"answer":      "",
+					"word_count":  len(content),
+					"tokens":      len(content) / 4,
+					"keywords":    terms,
+					"hit_count":   0,
+					"enabled":     true,
+					"status":      "completed",
+					"created_by":  "difytest",
+					"created_at":  doc.CreatedAt,
+					"document": map[string]string{
+						"id":               doc.ID,
+						"data_source_type": doc.DataSourceType,
+						"name":             doc.Name,
+					},
+				},
+				"score":         score,
+				"tsne_position": nil,
+				"child_chunks":  []interface{}{},
+			}})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	if len(hits) > topK {
+		hits = hits[:topK]
+	}
+	records := make([]map[string]interface{}, len(hits))
+	for i, h := range hits {
+		records[i] = h.record
+	}
+
+	if record {
+		ds.queries = append(ds.queries, &hitTestingQuery{
+			ID:            s.nextID(),
+			Content:       req.Query,
+			Source:        "hit_testing",
+			CreatedByRole: "account",
+			CreatedBy:     "difytest",
+			CreatedAt:     time.Now().Unix(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"query":   map[string]string{"content": req.Query},
+		"records": records,
+	})
+}
+
+// listHitTestingQueries 按时间倒序返回召回测试历史
+func (s *Server) listHitTestingQueries(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset_not_found", "Dataset not found.")
+		return
+	}
+	all := make([]*hitTestingQuery, 0, len(ds.queries))
+	for i := len(ds.queries) - 1; i >= 0; i-- {
+		all = append(all, ds.queries[i])
+	}
+	page, limit := pagination(r)
+	data, hasMore := paginate(all, page, limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":     data,
+		"has_more": hasMore,
+		"limit":    limit,
+		"total":    len(all),
+		"page":     page,
+	})
+}
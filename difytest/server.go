@@ -0,0 +1,368 @@
+// Package difytest 提供进程内的 Dify 模拟服务，用于在不依赖真实 Dify 的情况下测试 dify 客户端及其使用方。
+//
+// 模拟服务覆盖登录、refresh token、知识库 API 密钥、知识库与文档、应用、模型配置、
+// 应用 API 密钥、工作流调用（阻塞与 SSE）以及标注，并支持注入失败（401、429、500、慢响应、慢流）：
+//
+//	server := difytest.NewServer()
+//	defer server.Close()
+//	client, err := dify.NewClient(server.URL, difytest.DefaultEmail, difytest.DefaultPassword)
+package difytest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultEmail    = "test@example.com" // 默认登录邮箱
+	DefaultPassword = "password"         // 默认登录密码
+)
+
+// WorkflowFunc 模拟工作流的执行逻辑，返回工作流的 outputs；返回错误时工作流以 failed 状态结束
+type WorkflowFunc func(appID string, inputs map[string]interface{}) (map[string]interface{}, error)
+
+// Server 进程内的 Dify 模拟服务
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	email    string
+	password string
+	workflow WorkflowFunc
+	seq      int
+
+	fileSizeLimit int // 文档大小限制（MB）
+
+	accessTokens   map[string]bool
+	refreshTokens  map[string]bool
+	datasetAPIKeys []*apiKey
+	appAPIKeys     map[string]*apiKey // token -> key
+	datasets       map[string]*dataset
+	tags           map[string]*tag
+	crawlJobs      map[string]*crawlJob
+	uploadedFiles  map[string]*uploadedFile
+	apps           map[string]*app
+	workflowRuns   map[string]*workflowRun
+
+	failures    []*Failure
+	streamDelay time.Duration
+	requests    []Request
+	routes      []route
+}
+
+// Option 配置模拟服务
+type Option func(*Server)
+
+// WithCredentials 设置允许登录的邮箱和密码
+func WithCredentials(email, password string) Option {
+	return func(s *Server) {
+		s.email = email
+		s.password = password
+	}
+}
+
+// WithWorkflow 设置工作流的执行逻辑，默认把 inputs 原样作为 outputs 返回，并附加 text 输出
+func WithWorkflow(f WorkflowFunc) Option {
+	return func(s *Server) {
+		s.workflow = f
+	}
+}
+
+// WithFileSizeLimit 设置文档大小限制（MB），默认为 15
+func WithFileSizeLimit(mb int) Option {
+	return func(s *Server) {
+		s.fileSizeLimit = mb
+	}
+}
+
+// NewServer 启动模拟服务，使用完毕后需要调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		email:         DefaultEmail,
+		password:      DefaultPassword,
+		workflow:      echoWorkflow,
+		fileSizeLimit: 15,
+		accessTokens:  map[string]bool{},
+		refreshTokens: map[string]bool{},
+		appAPIKeys:    map[string]*apiKey{},
+		datasets:      map[string]*dataset{},
+		tags:          map[string]*tag{},
+		crawlJobs:     map[string]*crawlJob{},
+		uploadedFiles: map[string]*uploadedFile{},
+		apps:          map[string]*app{},
+		workflowRuns:  map[string]*workflowRun{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.registerRoutes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Failure 注入的失败规则
+type Failure struct {
+	Method  string        // 请求方法，为空时匹配任意方法
+	Path    string        // 请求路径，以 * 结尾时按前缀匹配
+	Status  int           // 返回的状态码，为 0 时仅延迟、不改变响应
+	Code    string        // 错误响应中的 code，默认根据状态码生成
+	Message string        // 错误响应中的 message
+	Times   int           // 生效次数，为 0 时一直生效
+	Delay   time.Duration // 响应前的延迟
+	Header  http.Header   // 错误响应附带的响应头，如 Retry-After
+	Apply   bool          // 为 true 时先正常处理请求再返回错误，模拟请求已生效但响应失败
+
+	hits int
+}
+
+// Fail 注入失败规则，按注入顺序匹配
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// FailNext 使下一次匹配 method 和 path 的请求返回 status
+func (s *Server) FailNext(method, path string, status int) {
+	s.Fail(Failure{Method: method, Path: path, Status: status, Times: 1})
+}
+
+// ClearFailures 清除所有注入的失败规则
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// SetStreamDelay 设置 SSE 流中相邻事件之间的延迟，用于模拟慢流
+func (s *Server) SetStreamDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streamDelay = d
+}
+
+// ExpireAccessTokens 使所有已签发的 console access token 失效，之后的 console 请求返回 401，直到客户端刷新 token
+func (s *Server) ExpireAccessTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessTokens = map[string]bool{}
+}
+
+// Request 模拟服务收到的请求
+type Request struct {
+	Method        string
+	Path          string
+	Query         url.Values
+	Authorization string
+}
+
+// Requests 返回模拟服务收到的全部请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Authorization: r.Header.Get("Authorization")})
+	failure := s.matchFailure(r)
+	s.mu.Unlock()
+
+	if failure != nil {
+		if failure.Delay > 0 {
+			select {
+			case <-time.After(failure.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if failure.Status != 0 {
+			code := failure.Code
+			if code == "" {
+				code = strings.ReplaceAll(strings.ToLower(http.StatusText(failure.Status)), " ", "_")
+			}
+			message := failure.Message
+			if message == "" {
+				message = http.StatusText(failure.Status)
+			}
+			if failure.Apply {
+				s.route(httptest.NewRecorder(), r)
+			}
+			for key, values := range failure.Header {
+				w.Header()[key] = values
+			}
+			writeError(w, failure.Status, code, message)
+			return
+		}
+	}
+	s.route(w, r)
+}
+
+// route 把请求交给匹配的处理函数
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	for _, rt := range s.routes {
+		if rt.method != r.Method {
+			continue
+		}
+		params, ok := matchPath(rt.pattern, r.URL.Path)
+		if !ok {
+			continue
+		}
+		if !s.authorize(rt.auth, r) {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "Access token is invalid")
+			return
+		}
+		rt.handler(w, r, params)
+		return
+	}
+	writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("%s %s is not supported by difytest", r.Method, r.URL.Path))
+}
+
+func (s *Server) matchFailure(r *http.Request) *Failure {
+	for _, f := range s.failures {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if strings.HasSuffix(f.Path, "*") {
+			if !strings.HasPrefix(r.URL.Path, strings.TrimSuffix(f.Path, "*")) {
+				continue
+			}
+		} else if f.Path != r.URL.Path {
+			continue
+		}
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		f.hits++
+		return f
+	}
+	return nil
+}
+
+// authScope 接口使用的鉴权方式
+type authScope int
+
+const (
+	authNone authScope = iota
+	authConsole
+	authDataset
+	authApp
+)
+
+func (s *Server) authorize(scope authScope, r *http.Request) bool {
+	if scope == authNone {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch scope {
+	case authConsole:
+		return s.accessTokens[token]
+	case authDataset:
+		for _, key := range s.datasetAPIKeys {
+			if key.Token == token {
+				return true
+			}
+		}
+		return false
+	case authApp:
+		_, ok := s.appAPIKeys[token]
+		return ok
+	}
+	return false
+}
+
+// appIDByToken 返回应用 API 密钥对应的应用ID
+func (s *Server) appIDByToken(r *http.Request) string {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.appAPIKeys[token]; ok {
+		return key.appID
+	}
+	return ""
+}
+
+type route struct {
+	method  string
+	pattern string
+	auth    authScope
+	handler func(w http.ResponseWriter, r *http.Request, params map[string]string)
+}
+
+func (s *Server) handle(method, pattern string, auth authScope, handler func(w http.ResponseWriter, r *http.Request, params map[string]string)) {
+	s.routes = append(s.routes, route{method: method, pattern: pattern, auth: auth, handler: handler})
+}
+
+// matchPath 匹配形如 /v1/datasets/{dataset_id}/documents 的路径模板
+func matchPath(pattern, path string) (map[string]string, bool) {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternParts) != len(pathParts) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, part := range patternParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			params[strings.Trim(part, "{}")] = pathParts[i]
+			continue
+		}
+		if part != pathParts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// nextID 生成 UUID 格式的自增ID，调用方需持有锁
+func (s *Server) nextID() string {
+	s.seq++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.seq, s.seq)
+}
+
+func randomToken(prefix string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"code":    code,
+		"message": message,
+		"status":  status,
+	})
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_param", err.Error())
+		return false
+	}
+	return true
+}
+
+func echoWorkflow(appID string, inputs map[string]interface{}) (map[string]interface{}, error) {
+	outputs := map[string]interface{}{}
+	for k, v := range inputs {
+		outputs[k] = v
+	}
+	outputs["text"] = fmt.Sprintf("difytest workflow of app %s finished", appID)
+	return outputs, nil
+}
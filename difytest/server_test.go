@@ -0,0 +1,52 @@
+package difytest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchPath(t *testing.T) {
+	params, ok := matchPath("/v1/datasets/{dataset_id}/documents/{batch}/indexing-status", "/v1/datasets/ds/documents/20240101/indexing-status")
+	if !ok || params["dataset_id"] != "ds" || params["batch"] != "20240101" {
+		t.Fatalf("unexpected match %v %v", params, ok)
+	}
+	if _, ok := matchPath("/v1/datasets/{dataset_id}/documents", "/v1/datasets/ds"); ok {
+		t.Fatal("paths with a different length must not match")
+	}
+}
+
+func TestFailure(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	login := func() *http.Response {
+		body := `{"email":"` + DefaultEmail + `","password":"` + DefaultPassword + `"}`
+		resp, err := http.Post(server.URL+"/console/api/login", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	server.Fail(Failure{Path: "/console/*", Status: http.StatusServiceUnavailable, Times: 2})
+	for i := 0; i < 2; i++ {
+		if resp := login(); resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("attempt %d: expected 503, got %d", i, resp.StatusCode)
+		}
+	}
+	if resp := login(); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 once the failure is used up, got %d", resp.StatusCode)
+	}
+
+	server.Fail(Failure{Method: http.MethodPost, Path: "/console/api/login", Delay: 30 * time.Millisecond})
+	start := time.Now()
+	if resp := login(); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a delayed 200, got %d", resp.StatusCode)
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Fatal("expected the response to be delayed")
+	}
+}
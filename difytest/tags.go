@@ -0,0 +1,197 @@
+package difytest
+
+import (
+	"net/http"
+	"sort"
+	"unicode/utf8"
+)
+
+type tag struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	BindingCount int    `json:"binding_count"`
+}
+
+func (s *Server) registerTagRoutes() {
+	s.handle(http.MethodGet, "/v1/datasets/tags", authDataset, s.listTags)
+	s.handle(http.MethodPost, "/v1/datasets/tags", authDataset, s.createTag)
+	s.handle(http.MethodPatch, "/v1/datasets/tags", authDataset, s.updateTag)
+	s.handle(http.MethodDelete, "/v1/datasets/tags", authDataset, s.deleteTag)
+	s.handle(http.MethodPost, "/v1/datasets/tags/binding", authDataset, s.bindTags)
+	s.handle(http.MethodPost, "/v1/datasets/tags/unbinding", authDataset, s.unbindTag)
+	s.handle(http.MethodGet, "/v1/datasets/{dataset_id}/tags", authDataset, s.getDatasetTags)
+}
+
+// datasetTags 返回知识库绑定的标签，调用方需持有锁
+func (s *Server) datasetTags(ds *dataset) []*tag {
+	tags := []*tag{}
+	for _, id := range ds.tagIDs {
+		if t, ok := s.tags[id]; ok {
+			tags = append(tags, s.countBindings(t))
+		}
+	}
+	return tags
+}
+
+// countBindings 更新标签绑定的知识库数量，调用方需持有锁
+func (s *Server) countBindings(t *tag) *tag {
+	t.BindingCount = 0
+	for _, ds := range s.datasets {
+		for _, id := range ds.tagIDs {
+			if id == t.ID {
+				t.BindingCount++
+			}
+		}
+	}
+	return t
+}
+
+func (s *Server) listTags(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags := make([]*tag, 0, len(s.tags))
+	for _, t := range s.tags {
+		tags = append(tags, s.countBindings(t))
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	writeJSON(w, http.StatusOK, tags)
+}
+
+func (s *Server) createTag(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > 50 {
+		writeError(w, http.StatusBadRequest, "invalid_param", "Tag name must be between 1 to 50 characters.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &tag{ID: s.nextID(), Name: req.Name, Type: "knowledge"}
+	s.tags[t.ID] = t
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) updateTag(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		TagID string `json:"tag_id"`
+		Name  string `json:"name"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > 50 {
+		writeError(w, http.StatusBadRequest, "invalid_param", "Tag name must be between 1 to 50 characters.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tags[req.TagID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "Tag not found.")
+		return
+	}
+	t.Name = req.Name
+	writeJSON(w, http.StatusOK, s.countBindings(t))
+}
+
+func (s *Server) deleteTag(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		TagID string `json:"tag_id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tags[req.TagID]; !ok {
+		writeError(w, http.StatusNotFound, "not_found", "Tag not found.")
+		return
+	}
+	delete(s.tags, req.TagID)
+	for _, ds := range s.datasets {
+		ds.tagIDs = removeString(ds.tagIDs, req.TagID)
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+func (s *Server) bindTags(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		TagIDs   []string `json:"tag_ids"`
+		TargetID string   `json:"target_id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[req.TargetID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "Dataset not found.")
+		return
+	}
+	for _, id := range req.TagIDs {
+		if _, ok := s.tags[id]; !ok {
+			writeError(w, http.StatusNotFound, "not_found", "Tag not found.")
+			return
+		}
+	}
+	for _, id := range req.TagIDs {
+		ds.tagIDs = append(removeString(ds.tagIDs, id), id)
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+func (s *Server) unbindTag(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		TagID    string `json:"tag_id"`
+		TargetID string `json:"target_id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[req.TargetID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "Dataset not found.")
+		return
+	}
+	ds.tagIDs = removeString(ds.tagIDs, req.TagID)
+	writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+func (s *Server) getDatasetTags(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datasets[params["dataset_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "Dataset not found.")
+		return
+	}
+	tags := s.datasetTags(ds)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": tags, "total": len(tags)})
+}
+
+func removeString(list []string, s string) []string {
+	out := list[:0]
+	for _, item := range list {
+		if item != s {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// GetIndexingStatus 获取一批文档的嵌入状态
+func (c *client) GetIndexingStatus(ctx context.Context, req *GetIndexingStatusRequest) (*Response[IndexingStatusResponse], error) {
+	var resp = &IndexingStatusResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get(fmt.Sprintf("/datasets/%s/documents/%s/indexing-status", req.DatasetID, req.Batch))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[IndexingStatusResponse](response, resp), nil
+}
+
+// WaitIndexing 轮询一批文档的嵌入状态，直到全部文档完成、出错或暂停
+func (c *client) WaitIndexing(ctx context.Context, req *GetIndexingStatusRequest, interval time.Duration) ([]DocumentIndexingStatus, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, err := c.GetIndexingStatus(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.IsSuccess() {
+			return nil, fmt.Errorf("failed to get indexing status with status %d: %s", resp.StatusCode(), resp.Message)
+		}
+		finished := true
+		for _, status := range resp.Result.Data {
+			if !status.IndexingStatus.Finished() {
+				finished = false
+			}
+		}
+		if finished {
+			return resp.Result.Data, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListDocuments 分页获取知识库中的文档，Keyword 按名称模糊搜索
+func (c *client) ListDocuments(ctx context.Context, req *ListDocumentsRequest) (*Response[ListDocumentsResponse], error) {
+	var resp = &ListDocumentsResponse{}
+	request := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp)
+	if req.Page > 0 {
+		request.SetQueryParam("page", strconv.Itoa(req.Page))
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Keyword != "" {
+		request.SetQueryParam("keyword", req.Keyword)
+	}
+	response, err := request.Get(fmt.Sprintf("/datasets/%s/documents", req.DatasetID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ListDocumentsResponse](response, resp), nil
+}
+
+// listAllDocuments 逐页获取知识库中名称包含 keyword 的全部文档，keyword 为空时返回全部文档
+func (c *client) listAllDocuments(ctx context.Context, datasetID, keyword string) ([]Document, error) {
+	var docs []Document
+	for page := 1; ; page++ {
+		resp, err := c.ListDocuments(ctx, &ListDocumentsRequest{DatasetID: datasetID, Keyword: keyword, Page: page, Limit: 100})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		if !resp.IsSuccess() {
+			return nil, fmt.Errorf("failed to list documents with status %d: %s", resp.StatusCode(), resp.Message)
+		}
+		docs = append(docs, resp.Result.Data...)
+		if !resp.Result.HasMore || len(resp.Result.Data) == 0 {
+			return docs, nil
+		}
+	}
+}
+
+// findDocumentsByName 返回名称与 name 完全相同的文档
+func (c *client) findDocumentsByName(ctx context.Context, datasetID, name string) ([]Document, error) {
+	docs, err := c.listAllDocuments(ctx, datasetID, name)
+	if err != nil {
+		return nil, err
+	}
+	var found []Document
+	for _, doc := range docs {
+		if doc.Name == name {
+			found = append(found, doc)
+		}
+	}
+	return found, nil
+}
+
+// DeleteDocument 删除文档
+func (c *client) DeleteDocument(ctx context.Context, req *DeleteDocumentRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp).
+		Delete(fmt.Sprintf("/datasets/%s/documents/%s", req.DatasetID, req.DocumentID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// UpdateByFile 通过文件更新文档，更新后会重新分段和嵌入
+func (c *client) UpdateByFile(ctx context.Context, req *UpdateByFileRequest) (*Response[CreateByFileResponse], error) {
+	if req.ProcessRule != nil {
+		if err := req.ProcessRule.Validate(req.DocForm); err != nil {
+			return nil, err
+		}
+	}
+	bytes, jsonErr := json.Marshal(req)
+	if jsonErr != nil {
+		return nil, fmt.Errorf("failed to marshal UpdateByFileRequest: %w", jsonErr)
+	}
+	var resp = &CreateByFileResponse{}
+	response, err := c.postDocumentFile(ctx, fmt.Sprintf("/datasets/%s/documents/%s/update-by-file", req.DatasetID, req.DocumentID), bytes, &documentFile{
+		Filename:    req.Filename,
+		Body:        req.FileBody,
+		Size:        req.FileSize,
+		MaxSize:     req.MaxFileSize,
+		ContentType: req.ContentType,
+		Progress:    req.Progress,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[CreateByFileResponse](response, resp), nil
+}
+
+// UpdateDocumentsStatus 批量启用、禁用、归档或取消归档文档
+func (c *client) UpdateDocumentsStatus(ctx context.Context, req *UpdateDocumentsStatusRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Patch(fmt.Sprintf("/datasets/%s/documents/status/%s", req.DatasetID, req.Action))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// IndexingStatus 文档嵌入状态
+type IndexingStatus string
+
+const (
+	IndexingStatusWaiting   IndexingStatus = "waiting"
+	IndexingStatusParsing   IndexingStatus = "parsing"
+	IndexingStatusCleaning  IndexingStatus = "cleaning"
+	IndexingStatusSplitting IndexingStatus = "splitting"
+	IndexingStatusIndexing  IndexingStatus = "indexing"
+	IndexingStatusCompleted IndexingStatus = "completed"
+	IndexingStatusError     IndexingStatus = "error"
+	IndexingStatusPaused    IndexingStatus = "paused"
+)
+
+// Finished 嵌入已结束（完成、出错或暂停）
+func (s IndexingStatus) Finished() bool {
+	return s == IndexingStatusCompleted || s == IndexingStatusError || s == IndexingStatusPaused
+}
+
+// GetIndexingStatusRequest 获取文档嵌入状态请求
+type GetIndexingStatusRequest struct {
+	DatasetID string // 知识库ID
+	Batch     string // 上传文档时返回的批次号
+}
+
+// IndexingStatusResponse 文档嵌入状态响应
+type IndexingStatusResponse struct {
+	Data []DocumentIndexingStatus `json:"data"`
+}
+
+// DocumentIndexingStatus 单个文档的嵌入状态，时间为 Unix 时间戳（秒）
+type DocumentIndexingStatus struct {
+	ID                   string         `json:"id"`
+	IndexingStatus       IndexingStatus `json:"indexing_status"`
+	ProcessingStartedAt  float64        `json:"processing_started_at"`
+	ParsingCompletedAt   float64        `json:"parsing_completed_at"`
+	CleaningCompletedAt  float64        `json:"cleaning_completed_at"`
+	SplittingCompletedAt float64        `json:"splitting_completed_at"`
+	CompletedAt          float64        `json:"completed_at"`
+	PausedAt             float64        `json:"paused_at"`
+	Error                string         `json:"error"`
+	StoppedAt            float64        `json:"stopped_at"`
+	CompletedSegments    int            `json:"completed_segments"`
+	TotalSegments        int            `json:"total_segments"`
+}
+
+// ListDocumentsRequest 获取文档列表请求
+type ListDocumentsRequest struct {
+	DatasetID string // 知识库ID
+	Keyword   string // 搜索关键字，按文档名称匹配
+	Page      int    // 页码，默认 1
+	Limit     int    // 每页数量，默认 20
+}
+
+// ListDocumentsResponse 文档列表响应
+type ListDocumentsResponse struct {
+	Data    []Document `json:"data"`
+	HasMore bool       `json:"has_more"`
+	Limit   int        `json:"limit"`
+	Total   int        `json:"total"`
+	Page    int        `json:"page"`
+}
+
+// DeleteDocumentRequest 删除文档请求
+type DeleteDocumentRequest struct {
+	DatasetID  string // 知识库ID
+	DocumentID string // 文档ID
+}
+
+// UpdateByFileRequest 通过文件更新文档请求，响应与 CreateByFile 相同
+type UpdateByFileRequest struct {
+	DatasetID   string               `json:"-"`                      // 知识库ID
+	DocumentID  string               `json:"-"`                      // 文档ID
+	Filename    string               `json:"-"`                      // 文件名
+	FileBody    io.Reader            `json:"-"`                      // 文件内容，以流式上传
+	FileSize    int64                `json:"-"`                      // 文件大小，含义与 CreateByFileRequest 相同
+	MaxFileSize int64                `json:"-"`                      // 文件大小限制（字节），为 0 时使用服务端的上传限制
+	ContentType string               `json:"-"`                      // 文件的 MIME 类型，为空时自动识别
+	Progress    func(UploadProgress) `json:"-"`                      // 上传进度回调
+	Name        string               `json:"name,omitempty"`         // 文档名称，为空时沿用原名称
+	DocForm     DocForm              `json:"doc_form,omitempty"`     // 索引内容的形式
+	DocLanguage string               `json:"doc_language,omitempty"` // 在 Q&A 模式下，指定文档的语言
+	ProcessRule *ProcessRule         `json:"process_rule,omitempty"` // 文档处理规则，为空时沿用原规则
+}
+
+// DocumentStatusAction 文档状态操作
+type DocumentStatusAction string
+
+const (
+	DocumentStatusActionEnable    DocumentStatusAction = "enable"
+	DocumentStatusActionDisable   DocumentStatusAction = "disable"
+	DocumentStatusActionArchive   DocumentStatusAction = "archive"
+	DocumentStatusActionUnArchive DocumentStatusAction = "un_archive"
+)
+
+// UpdateDocumentsStatusRequest 批量修改文档状态请求
+type UpdateDocumentsStatusRequest struct {
+	DatasetID   string               `json:"-"`            // 知识库ID
+	Action      DocumentStatusAction `json:"-"`            // 状态操作
+	DocumentIDs []string             `json:"document_ids"` // 文档ID列表
+}
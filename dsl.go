@@ -0,0 +1,323 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"resty.dev/v3"
+	"strings"
+)
+
+// ExportAppDSL 导出应用的 DSL（YAML）
+func (c *client) ExportAppDSL(ctx context.Context, req *ExportAppDSLRequest) (*Response[ExportAppDSLResponse], error) {
+	var resultErr error
+	var resp = &ExportAppDSLResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetQueryParam("include_secret", fmt.Sprintf("%t", req.IncludeSecret)).
+			SetResult(&resp).
+			Get(fmt.Sprintf("/console/api/apps/%s/export", req.AppID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to export app dsl: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to export app dsl with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ExportAppDSLResponse](finalResponse, resp), nil
+}
+
+// ImportAppDSL 导入应用 DSL
+// AppID 为空时创建新应用，否则覆盖指定应用。
+// 当 DSL 版本与服务端不一致时服务端返回 pending 状态，ConfirmOnVersionMismatch 为 true 时自动确认导入
+func (c *client) ImportAppDSL(ctx context.Context, req *ImportAppDSLRequest) (*Response[ImportAppDSLResponse], error) {
+	var resultErr error
+	var resp = &ImportAppDSLResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(&importAppDSLInternalRequest{
+				Mode:           "yaml-content",
+				YAMLContent:    req.YAMLContent,
+				AppID:          req.AppID,
+				Name:           req.Name,
+				Description:    req.Description,
+				IconType:       req.IconType,
+				Icon:           req.Icon,
+				IconBackground: req.IconBackground,
+			}).
+			SetResult(&resp).
+			Post("/console/api/apps/imports")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to import app dsl: %w", err)
+			return response, err
+		}
+
+		// 版本不一致时服务端返回 202 和 pending 状态，不视为错误
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to import app dsl with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	if resp.Status == ImportStatusPending && req.ConfirmOnVersionMismatch {
+		return c.ConfirmAppDSLImport(ctx, &ConfirmAppDSLImportRequest{ImportID: resp.ID})
+	}
+
+	return buildResponse[ImportAppDSLResponse](finalResponse, resp), nil
+}
+
+// ConfirmAppDSLImport 确认处于 pending 状态的 DSL 导入
+func (c *client) ConfirmAppDSLImport(ctx context.Context, req *ConfirmAppDSLImportRequest) (*Response[ImportAppDSLResponse], error) {
+	var resultErr error
+	var resp = &ImportAppDSLResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetResult(&resp).
+			Post(fmt.Sprintf("/console/api/apps/imports/%s/confirm", req.ImportID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to confirm app dsl import: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to confirm app dsl import with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ImportAppDSLResponse](finalResponse, resp), nil
+}
+
+// DiffAppDSL 比较线上应用的 DSL 与本地 DSL 内容，返回按行的差异
+// 没有差异时返回空切片
+func (c *client) DiffAppDSL(ctx context.Context, appID string, localYAML string) ([]DSLDiffLine, error) {
+	resp, err := c.ExportAppDSL(ctx, &ExportAppDSLRequest{AppID: appID})
+	if err != nil {
+		return nil, err
+	}
+	return DiffDSL(resp.Result.Data, localYAML), nil
+}
+
+// DSLDiffOp 差异行的类型
+type DSLDiffOp string
+
+const (
+	DSLDiffDelete DSLDiffOp = "-" // 仅存在于线上
+	DSLDiffInsert DSLDiffOp = "+" // 仅存在于本地
+)
+
+// DSLDiffLine 一行差异
+type DSLDiffLine struct {
+	Op   DSLDiffOp
+	Line string
+}
+
+func (l DSLDiffLine) String() string {
+	return string(l.Op) + l.Line
+}
+
+// DiffDSL 按行比较两份 DSL 内容（基于最长公共子序列），仅返回有变化的行
+// 行尾空白和末尾空行不参与比较。使用 Hirschberg 算法，内存占用与行数成线性关系
+func DiffDSL(remote, local string) []DSLDiffLine {
+	return diffDSLLines(splitDSLLines(remote), splitDSLLines(local), nil)
+}
+
+// diffDSLLines 把 a 到 b 的差异追加到 diff 中
+func diffDSLLines(a, b []string, diff []DSLDiffLine) []DSLDiffLine {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			diff = append(diff, DSLDiffLine{Op: DSLDiffInsert, Line: line})
+		}
+		return diff
+	case len(b) == 0:
+		for _, line := range a {
+			diff = append(diff, DSLDiffLine{Op: DSLDiffDelete, Line: line})
+		}
+		return diff
+	case len(a) == 1:
+		for k, line := range b {
+			if line == a[0] {
+				diff = diffDSLLines(nil, b[:k], diff)
+				return diffDSLLines(nil, b[k+1:], diff)
+			}
+		}
+		diff = append(diff, DSLDiffLine{Op: DSLDiffDelete, Line: a[0]})
+		return diffDSLLines(nil, b, diff)
+	}
+
+	// 以 a 的中间行切分，找到使两侧公共子序列之和最大的 b 的切分点
+	mid := len(a) / 2
+	forward := lcsLengths(a[:mid], b, false)
+	backward := lcsLengths(a[mid:], b, true)
+	split, best := 0, -1
+	for k := 0; k <= len(b); k++ {
+		if n := forward[k] + backward[len(b)-k]; n > best {
+			split, best = k, n
+		}
+	}
+	diff = diffDSLLines(a[:mid], b[:split], diff)
+	return diffDSLLines(a[mid:], b[split:], diff)
+}
+
+// lcsLengths 返回 a 与 b 每个前缀的最长公共子序列长度，reverse 为 true 时从尾部开始比较（即后缀）
+func lcsLengths(a, b []string, reverse bool) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		x := a[i]
+		if reverse {
+			x = a[len(a)-1-i]
+		}
+		for j := 1; j <= len(b); j++ {
+			y := b[j-1]
+			if reverse {
+				y = b[len(b)-j]
+			}
+			switch {
+			case x == y:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+func splitDSLLines(s string) []string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// ImportStatus DSL 导入状态
+type ImportStatus string
+
+const (
+	ImportStatusCompleted             ImportStatus = "completed"               // 导入完成
+	ImportStatusCompletedWithWarnings ImportStatus = "completed-with-warnings" // 导入完成但存在警告（例如 DSL 版本较旧）
+	ImportStatusPending               ImportStatus = "pending"                 // DSL 版本不一致，等待确认
+	ImportStatusFailed                ImportStatus = "failed"                  // 导入失败
+)
+
+// ExportAppDSLRequest 导出应用 DSL 请求
+type ExportAppDSLRequest struct {
+	AppID         string // 应用ID
+	IncludeSecret bool   // 是否包含密钥类环境变量的值
+}
+
+// ExportAppDSLResponse 导出应用 DSL 响应
+type ExportAppDSLResponse struct {
+	Data string `json:"data"` // DSL YAML 内容
+}
+
+// ImportAppDSLRequest 导入应用 DSL 请求
+type ImportAppDSLRequest struct {
+	YAMLContent              string // DSL YAML 内容
+	AppID                    string // 要覆盖的应用ID，为空时创建新应用
+	Name                     string // 覆盖 DSL 中的应用名称（可选）
+	Description              string // 覆盖 DSL 中的应用描述（可选）
+	IconType                 string
+	Icon                     string
+	IconBackground           string
+	ConfirmOnVersionMismatch bool // DSL 版本不一致时自动确认导入
+}
+
+// importAppDSLInternalRequest 内部使用的导入请求结构
+type importAppDSLInternalRequest struct {
+	Mode           string `json:"mode"`
+	YAMLContent    string `json:"yaml_content"`
+	AppID          string `json:"app_id,omitempty"`
+	Name           string `json:"name,omitempty"`
+	Description    string `json:"description,omitempty"`
+	IconType       string `json:"icon_type,omitempty"`
+	Icon           string `json:"icon,omitempty"`
+	IconBackground string `json:"icon_background,omitempty"`
+}
+
+// ImportAppDSLResponse 导入应用 DSL 响应
+type ImportAppDSLResponse struct {
+	ID                 string       `json:"id"` // 导入任务ID，用于确认 pending 状态的导入
+	Status             ImportStatus `json:"status"`
+	AppID              string       `json:"app_id"`
+	AppMode            string       `json:"app_mode"`
+	CurrentDSLVersion  string       `json:"current_dsl_version"`
+	ImportedDSLVersion string       `json:"imported_dsl_version"`
+	Error              string       `json:"error"`
+}
+
+// ConfirmAppDSLImportRequest 确认 DSL 导入请求
+type ConfirmAppDSLImportRequest struct {
+	ImportID string // 导入任务ID
+}
@@ -0,0 +1,94 @@
+package dify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiffDSL(t *testing.T) {
+	remote := "app:\n  name: demo\n  mode: workflow\nversion: 0.1.5\n"
+	local := "app:\n  name: demo-prod  \n  mode: workflow\nversion: 0.1.5\nkind: app\n\n"
+
+	diff := DiffDSL(remote, local)
+	want := []string{"-  name: demo", "+  name: demo-prod", "+kind: app"}
+	if len(diff) != len(want) {
+		t.Fatalf("Expected %d diff lines, got %v", len(want), diff)
+	}
+	for i, line := range diff {
+		if line.String() != want[i] {
+			t.Errorf("Line %d: expected '%s', got '%s'", i, want[i], line)
+		}
+	}
+
+	if diff := DiffDSL(remote, remote+"\n"); len(diff) != 0 {
+		t.Errorf("Expected no diff for identical DSL, got %v", diff)
+	}
+}
+
+func TestDiffDSLMinimal(t *testing.T) {
+	// 与完整的动态规划结果比较，差异行数应等于两侧行数减去最长公共子序列长度
+	a := []string{"a", "b", "c", "a", "b", "b", "a", "d", "e", "c"}
+	b := []string{"c", "b", "a", "b", "a", "c", "e", "d", "b"}
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := DiffDSL(strings.Join(a, "\n"), strings.Join(b, "\n"))
+	var deleted, inserted []string
+	for _, line := range diff {
+		if line.Op == DSLDiffDelete {
+			deleted = append(deleted, line.Line)
+		} else {
+			inserted = append(inserted, line.Line)
+		}
+	}
+	if len(deleted) != len(a)-lcs[0][0] || len(inserted) != len(b)-lcs[0][0] {
+		t.Fatalf("Expected minimal diff (lcs %d), got %v", lcs[0][0], diff)
+	}
+	if !isSubsequence(deleted, a) || !isSubsequence(inserted, b) {
+		t.Errorf("Diff lines out of order: %v", diff)
+	}
+}
+
+func TestDiffDSLLarge(t *testing.T) {
+	var remote, local strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&remote, "line: %d\n", i)
+		if i%1000 == 0 {
+			fmt.Fprintf(&local, "changed: %d\n", i)
+		} else {
+			fmt.Fprintf(&local, "line: %d\n", i)
+		}
+	}
+	diff := DiffDSL(remote.String(), local.String())
+	if len(diff) != 10 {
+		t.Fatalf("Expected 10 diff lines, got %d", len(diff))
+	}
+	if diff[0].String() != "-line: 0" || diff[1].String() != "+changed: 0" {
+		t.Errorf("Unexpected first change: %v", diff[:2])
+	}
+}
+
+func isSubsequence(sub, s []string) bool {
+	i := 0
+	for _, line := range s {
+		if i < len(sub) && sub[i] == line {
+			i++
+		}
+	}
+	return i == len(sub)
+}
@@ -43,8 +43,17 @@ const (
 type ProcessMode string
 
 const (
-	ProcessModeAutomatic ProcessMode = "automatic" // 自动处理
-	ProcessModeManual    ProcessMode = "custom"    // 自定义
+	ProcessModeAutomatic    ProcessMode = "automatic"    // 自动处理
+	ProcessModeManual       ProcessMode = "custom"       // 自定义
+	ProcessModeHierarchical ProcessMode = "hierarchical" // 父子分段，仅用于 DocFormHierarchicalModel
+)
+
+// ParentMode 父子分段模式下父分段的召回方式
+type ParentMode string
+
+const (
+	ParentModeParagraph ParentMode = "paragraph" // 按分段标识符切分父分段
+	ParentModeFullDoc   ParentMode = "full-doc"  // 整个文档作为一个父分段
 )
 
 type PreProcessingRulesID string
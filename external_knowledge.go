@@ -0,0 +1,429 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"resty.dev/v3"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListExternalKnowledgeAPIs 分页获取已注册的外部知识库 API
+func (c *client) ListExternalKnowledgeAPIs(ctx context.Context, req *ListExternalKnowledgeAPIsRequest) (*Response[ListExternalKnowledgeAPIsResponse], error) {
+	var resultErr error
+	var resp = &ListExternalKnowledgeAPIsResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		request := c.console().
+			WithContext(ctx).
+			SetResult(&resp)
+		if req.Page > 0 {
+			request.SetQueryParam("page", strconv.Itoa(req.Page))
+		}
+		if req.Limit > 0 {
+			request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+		}
+		if req.Keyword != "" {
+			request.SetQueryParam("keyword", req.Keyword)
+		}
+		response, err := request.Get("/console/api/datasets/external-knowledge-api")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to list external knowledge apis: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to list external knowledge apis with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ListExternalKnowledgeAPIsResponse](finalResponse, resp), nil
+}
+
+// CreateExternalKnowledgeAPI 注册外部知识库 API
+// Dify 会在注册时校验 endpoint 的连通性
+func (c *client) CreateExternalKnowledgeAPI(ctx context.Context, req *CreateExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPI], error) {
+	var resultErr error
+	var resp = &ExternalKnowledgeAPI{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Post("/console/api/datasets/external-knowledge-api")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to create external knowledge api: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to create external knowledge api with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ExternalKnowledgeAPI](finalResponse, resp), nil
+}
+
+// GetExternalKnowledgeAPI 获取外部知识库 API 详情
+func (c *client) GetExternalKnowledgeAPI(ctx context.Context, req *GetExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPI], error) {
+	var resultErr error
+	var resp = &ExternalKnowledgeAPI{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetResult(&resp).
+			Get(fmt.Sprintf("/console/api/datasets/external-knowledge-api/%s", req.ExternalKnowledgeAPIID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to get external knowledge api: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to get external knowledge api with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ExternalKnowledgeAPI](finalResponse, resp), nil
+}
+
+// UpdateExternalKnowledgeAPI 修改外部知识库 API 的名称、endpoint 或 API Key
+func (c *client) UpdateExternalKnowledgeAPI(ctx context.Context, req *UpdateExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPI], error) {
+	var resultErr error
+	var resp = &ExternalKnowledgeAPI{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Patch(fmt.Sprintf("/console/api/datasets/external-knowledge-api/%s", req.ExternalKnowledgeAPIID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to update external knowledge api: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to update external knowledge api with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ExternalKnowledgeAPI](finalResponse, resp), nil
+}
+
+// DeleteExternalKnowledgeAPI 删除外部知识库 API
+func (c *client) DeleteExternalKnowledgeAPI(ctx context.Context, req *DeleteExternalKnowledgeAPIRequest) (*Response[ResultResponse], error) {
+	var resultErr error
+	var resp = &ResultResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetResult(&resp).
+			Delete(fmt.Sprintf("/console/api/datasets/external-knowledge-api/%s", req.ExternalKnowledgeAPIID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to delete external knowledge api: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to delete external knowledge api with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ResultResponse](finalResponse, resp), nil
+}
+
+// CheckExternalKnowledgeAPIUsage 检查外部知识库 API 是否被知识库引用
+func (c *client) CheckExternalKnowledgeAPIUsage(ctx context.Context, req *GetExternalKnowledgeAPIRequest) (*Response[ExternalKnowledgeAPIUsage], error) {
+	var resultErr error
+	var resp = &ExternalKnowledgeAPIUsage{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetResult(&resp).
+			Get(fmt.Sprintf("/console/api/datasets/external-knowledge-api/%s/use-check", req.ExternalKnowledgeAPIID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to check external knowledge api usage: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to check external knowledge api usage with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ExternalKnowledgeAPIUsage](finalResponse, resp), nil
+}
+
+// CreateExternalDataset 创建关联外部知识库的知识库
+func (c *client) CreateExternalDataset(ctx context.Context, req *CreateExternalDatasetRequest) (*Response[CreateEmptyDatasetResponse], error) {
+	var resultErr error
+	var resp = &CreateEmptyDatasetResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Post("/console/api/datasets/external")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to create external dataset: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to create external dataset with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[CreateEmptyDatasetResponse](finalResponse, resp), nil
+}
+
+// CheckExternalKnowledgeAPIConnectivity 直接向外部知识库 endpoint 发送一次探测检索，检查连通性和 API Key
+// 探测请求使用不存在的知识库ID，返回知识库不存在（2001）同样视为连通且鉴权通过
+func CheckExternalKnowledgeAPIConnectivity(ctx context.Context, settings ExternalKnowledgeAPISettings) (*ExternalKnowledgeAPIConnectivity, error) {
+	var errResp ExternalKnowledgeError
+	client := resty.New()
+	defer client.Close()
+	start := time.Now()
+	response, err := client.R().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetHeader("Authorization", "Bearer "+settings.APIKey).
+		SetBody(&ExternalRetrievalRequest{
+			KnowledgeID: "dify-go-connectivity-check",
+			Query:       "ping",
+			RetrievalSetting: ExternalRetrievalSetting{
+				TopK: 1,
+			},
+		}).
+		SetError(&errResp).
+		Post(strings.TrimRight(settings.Endpoint, "/") + "/retrieval")
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach external knowledge api %s: %w", settings.Endpoint, err)
+	}
+
+	result := &ExternalKnowledgeAPIConnectivity{
+		StatusCode: response.StatusCode(),
+		Latency:    time.Since(start),
+		ErrorCode:  errResp.ErrorCode,
+		ErrorMsg:   errResp.ErrorMsg,
+	}
+	switch {
+	case response.IsSuccess():
+		result.Authorized = true
+	case errResp.ErrorCode == ExternalKnowledgeErrorKnowledgeNotFound:
+		result.Authorized = true
+	}
+	return result, nil
+}
+
+// ExternalKnowledgeAPISettings 外部知识库 API 的连接配置
+type ExternalKnowledgeAPISettings struct {
+	Endpoint string `json:"endpoint"` // 外部知识库 API 的根地址，Dify 会请求 {endpoint}/retrieval
+	APIKey   string `json:"api_key"`
+}
+
+// ExternalKnowledgeAPI 外部知识库 API
+type ExternalKnowledgeAPI struct {
+	ID              string                       `json:"id"`
+	TenantID        string                       `json:"tenant_id"`
+	Name            string                       `json:"name"`
+	Description     string                       `json:"description"`
+	Settings        ExternalKnowledgeAPISettings `json:"settings"`
+	DatasetBindings []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"dataset_bindings"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListExternalKnowledgeAPIsRequest 获取外部知识库 API 列表请求
+type ListExternalKnowledgeAPIsRequest struct {
+	Page    int    // 页码，默认 1
+	Limit   int    // 每页数量，默认 20
+	Keyword string // 搜索关键字
+}
+
+// ListExternalKnowledgeAPIsResponse 外部知识库 API 列表响应
+type ListExternalKnowledgeAPIsResponse struct {
+	Data    []ExternalKnowledgeAPI `json:"data"`
+	HasMore bool                   `json:"has_more"`
+	Limit   int                    `json:"limit"`
+	Total   int                    `json:"total"`
+	Page    int                    `json:"page"`
+}
+
+// CreateExternalKnowledgeAPIRequest 注册外部知识库 API 请求
+type CreateExternalKnowledgeAPIRequest struct {
+	Name     string                       `json:"name"`
+	Settings ExternalKnowledgeAPISettings `json:"settings"`
+}
+
+// GetExternalKnowledgeAPIRequest 获取外部知识库 API 请求
+type GetExternalKnowledgeAPIRequest struct {
+	ExternalKnowledgeAPIID string // 外部知识库 API ID
+}
+
+// UpdateExternalKnowledgeAPIRequest 修改外部知识库 API 请求
+type UpdateExternalKnowledgeAPIRequest struct {
+	ExternalKnowledgeAPIID string                       `json:"-"` // 外部知识库 API ID，不包含在JSON中
+	Name                   string                       `json:"name"`
+	Settings               ExternalKnowledgeAPISettings `json:"settings"`
+}
+
+// DeleteExternalKnowledgeAPIRequest 删除外部知识库 API 请求
+type DeleteExternalKnowledgeAPIRequest struct {
+	ExternalKnowledgeAPIID string // 外部知识库 API ID
+}
+
+// ExternalKnowledgeAPIUsage 外部知识库 API 的引用情况
+type ExternalKnowledgeAPIUsage struct {
+	IsUsing bool `json:"is_using"`
+	Count   int  `json:"count"` // 引用该 API 的知识库数量
+}
+
+// CreateExternalDatasetRequest 创建外部知识库请求
+type CreateExternalDatasetRequest struct {
+	ExternalKnowledgeAPIID string                 `json:"external_knowledge_api_id"`
+	ExternalKnowledgeID    string                 `json:"external_knowledge_id"` // 外部系统中的知识库ID
+	Name                   string                 `json:"name"`
+	Description            string                 `json:"description,omitempty"`
+	ExternalRetrievalModel ExternalRetrievalModel `json:"external_retrieval_model"`
+}
+
+// ExternalRetrievalModel 外部知识库的检索配置
+type ExternalRetrievalModel struct {
+	TopK                  int     `json:"top_k"`
+	ScoreThreshold        float64 `json:"score_threshold"`
+	ScoreThresholdEnabled bool    `json:"score_threshold_enabled"`
+}
+
+// ExternalKnowledgeAPIConnectivity 外部知识库 API 的连通性检查结果
+type ExternalKnowledgeAPIConnectivity struct {
+	StatusCode int           // endpoint 返回的 HTTP 状态码
+	Authorized bool          // API Key 是否通过校验
+	ErrorCode  int           // endpoint 返回的错误码
+	ErrorMsg   string        // endpoint 返回的错误信息
+	Latency    time.Duration // 请求耗时
+}
@@ -0,0 +1,178 @@
+package dify
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// 外部知识库协议的错误码
+const (
+	ExternalKnowledgeErrorInvalidAuthHeader = 1001 // Authorization 请求头格式错误
+	ExternalKnowledgeErrorAuthFailed        = 1002 // 鉴权失败
+	ExternalKnowledgeErrorKnowledgeNotFound = 2001 // 知识库不存在
+
+	// 以下错误码不在协议中定义，取对应的 HTTP 状态码
+	ExternalKnowledgeErrorInvalidRequest   = 400 // 请求体无法解析
+	ExternalKnowledgeErrorMethodNotAllowed = 405 // 请求方法不是 POST
+	ExternalKnowledgeErrorInternal         = 500 // 检索失败
+)
+
+// ErrExternalKnowledgeNotFound Retriever 返回该错误时，handler 以 2001 错误码响应
+var ErrExternalKnowledgeNotFound = errors.New("knowledge does not exist")
+
+// ExternalRetriever 外部知识库的检索实现
+type ExternalRetriever interface {
+	Retrieve(ctx context.Context, req *ExternalRetrievalRequest) ([]ExternalKnowledgeRecord, error)
+}
+
+// ExternalRetrieverFunc 将函数适配为 ExternalRetriever
+type ExternalRetrieverFunc func(ctx context.Context, req *ExternalRetrievalRequest) ([]ExternalKnowledgeRecord, error)
+
+func (f ExternalRetrieverFunc) Retrieve(ctx context.Context, req *ExternalRetrievalRequest) ([]ExternalKnowledgeRecord, error) {
+	return f(ctx, req)
+}
+
+// NewExternalKnowledgeHandler 创建实现 Dify 外部知识库检索协议的 http.Handler
+// 注册到外部知识库 API 时 endpoint 填写 handler 挂载路径的上一级，Dify 会请求 {endpoint}/retrieval，例如：
+//
+//	http.Handle("/retrieval", dify.NewExternalKnowledgeHandler("my-api-key", retriever))
+//
+// handler 负责校验 API Key，并按 top_k 和 score_threshold 过滤检索结果。
+// 检索失败时只向 Dify 返回固定的错误信息，避免泄露后端细节，需要记录错误时使用 WithExternalKnowledgeErrorHandler
+func NewExternalKnowledgeHandler(apiKey string, retriever ExternalRetriever, opts ...ExternalKnowledgeHandlerOption) http.Handler {
+	h := &externalKnowledgeHandler{apiKey: apiKey, retriever: retriever}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ExternalKnowledgeHandlerOption 配置外部知识库 handler
+type ExternalKnowledgeHandlerOption func(*externalKnowledgeHandler)
+
+// WithExternalKnowledgeErrorHandler 设置 Retriever 返回错误（ErrExternalKnowledgeNotFound 除外）时的回调，用于记录日志
+func WithExternalKnowledgeErrorHandler(fn func(r *http.Request, err error)) ExternalKnowledgeHandlerOption {
+	return func(h *externalKnowledgeHandler) {
+		h.onError = fn
+	}
+}
+
+type externalKnowledgeHandler struct {
+	apiKey    string
+	retriever ExternalRetriever
+	onError   func(r *http.Request, err error)
+}
+
+func (h *externalKnowledgeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeExternalKnowledgeError(w, http.StatusMethodNotAllowed, ExternalKnowledgeErrorMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || scheme != "Bearer" || token == "" {
+		writeExternalKnowledgeError(w, http.StatusForbidden, ExternalKnowledgeErrorInvalidAuthHeader,
+			"Invalid Authorization header format. Expected 'Bearer <api-key>' format.")
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.apiKey)) != 1 {
+		writeExternalKnowledgeError(w, http.StatusForbidden, ExternalKnowledgeErrorAuthFailed, "Authorization failed")
+		return
+	}
+
+	var req ExternalRetrievalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeExternalKnowledgeError(w, http.StatusBadRequest, ExternalKnowledgeErrorInvalidRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	records, err := h.retriever.Retrieve(r.Context(), &req)
+	if errors.Is(err, ErrExternalKnowledgeNotFound) {
+		writeExternalKnowledgeError(w, http.StatusNotFound, ExternalKnowledgeErrorKnowledgeNotFound, "The knowledge does not exist.")
+		return
+	}
+	if err != nil {
+		if h.onError != nil {
+			h.onError(r, err)
+		}
+		writeExternalKnowledgeError(w, http.StatusInternalServerError, ExternalKnowledgeErrorInternal, "Internal error.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&ExternalRetrievalResponse{
+		Records: filterExternalRecords(records, req.RetrievalSetting),
+	})
+}
+
+// filterExternalRecords 按分数降序排列，丢弃低于 score_threshold 的记录并截取前 top_k 条
+func filterExternalRecords(records []ExternalKnowledgeRecord, setting ExternalRetrievalSetting) []ExternalKnowledgeRecord {
+	filtered := make([]ExternalKnowledgeRecord, 0, len(records))
+	for _, record := range records {
+		if record.Score >= setting.ScoreThreshold {
+			filtered = append(filtered, record)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Score > filtered[j].Score
+	})
+	if setting.TopK > 0 && len(filtered) > setting.TopK {
+		filtered = filtered[:setting.TopK]
+	}
+	return filtered
+}
+
+func writeExternalKnowledgeError(w http.ResponseWriter, status, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&ExternalKnowledgeError{ErrorCode: code, ErrorMsg: msg})
+}
+
+// ExternalRetrievalRequest Dify 发往外部知识库的检索请求
+type ExternalRetrievalRequest struct {
+	KnowledgeID       string                     `json:"knowledge_id"`
+	Query             string                     `json:"query"`
+	RetrievalSetting  ExternalRetrievalSetting   `json:"retrieval_setting"`
+	MetadataCondition *ExternalMetadataCondition `json:"metadata_condition,omitempty"`
+}
+
+// ExternalRetrievalSetting 检索参数
+type ExternalRetrievalSetting struct {
+	TopK           int     `json:"top_k"`
+	ScoreThreshold float64 `json:"score_threshold"`
+}
+
+// ExternalMetadataCondition 外部知识库检索的元数据过滤条件
+type ExternalMetadataCondition struct {
+	LogicalOperator LogicalOperator `json:"logical_operator"`
+	Conditions      []struct {
+		Name               []string           `json:"name"`
+		ComparisonOperator ComparisonOperator `json:"comparison_operator"`
+		Value              interface{}        `json:"value,omitempty"`
+	} `json:"conditions"`
+}
+
+// ExternalRetrievalResponse 外部知识库的检索响应
+type ExternalRetrievalResponse struct {
+	Records []ExternalKnowledgeRecord `json:"records"`
+}
+
+// ExternalKnowledgeRecord 外部知识库返回的一条记录
+type ExternalKnowledgeRecord struct {
+	Content  string                 `json:"content"`
+	Score    float64                `json:"score"` // 0 到 1 之间的相关性分数
+	Title    string                 `json:"title"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ExternalKnowledgeError 外部知识库协议的错误响应
+type ExternalKnowledgeError struct {
+	ErrorCode int    `json:"error_code"`
+	ErrorMsg  string `json:"error_msg"`
+}
@@ -0,0 +1,128 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExternalKnowledgeHandler(t *testing.T) {
+	logged := make(chan error, 1)
+	handler := NewExternalKnowledgeHandler("secret", ExternalRetrieverFunc(func(ctx context.Context, req *ExternalRetrievalRequest) ([]ExternalKnowledgeRecord, error) {
+		if req.KnowledgeID == "broken" {
+			return nil, errors.New("vector store unavailable")
+		}
+		if req.KnowledgeID != "kb-1" {
+			return nil, ErrExternalKnowledgeNotFound
+		}
+		return []ExternalKnowledgeRecord{
+			{Title: "low", Content: "c1", Score: 0.2},
+			{Title: "best", Content: "c2", Score: 0.9},
+			{Title: "good", Content: "c3", Score: 0.7},
+			{Title: "ok", Content: "c4", Score: 0.5},
+		}, nil
+	}), WithExternalKnowledgeErrorHandler(func(r *http.Request, err error) {
+		select {
+		case logged <- err:
+		default:
+		}
+	}))
+	mux := http.NewServeMux()
+	mux.Handle("/retrieval", handler)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	post := func(auth, body string) (*http.Response, map[string]interface{}) {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/retrieval", strings.NewReader(body))
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp, out
+	}
+
+	body := `{"knowledge_id":"kb-1","query":"q","retrieval_setting":{"top_k":2,"score_threshold":0.4}}`
+	resp, out := post("Bearer secret", body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	records := out["records"].([]interface{})
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if title := records[0].(map[string]interface{})["title"]; title != "best" {
+		t.Errorf("Expected best record first, got %v", title)
+	}
+
+	cases := []struct {
+		auth string
+		body string
+		code float64
+	}{
+		{"", body, ExternalKnowledgeErrorInvalidAuthHeader},
+		{"secret", body, ExternalKnowledgeErrorInvalidAuthHeader},
+		{"Bearer wrong", body, ExternalKnowledgeErrorAuthFailed},
+		{"Bearer secret", `{"knowledge_id":"missing","query":"q"}`, ExternalKnowledgeErrorKnowledgeNotFound},
+		{"Bearer secret", `not json`, ExternalKnowledgeErrorInvalidRequest},
+		{"Bearer secret", `{"knowledge_id":"broken","query":"q"}`, ExternalKnowledgeErrorInternal},
+	}
+	for _, c := range cases {
+		_, out := post(c.auth, c.body)
+		if out["error_code"] != c.code {
+			t.Errorf("auth %q: expected error code %v, got %v", c.auth, c.code, out["error_code"])
+		}
+	}
+
+	// 检索失败的细节只交给回调，不返回给 Dify
+	if _, out := post("Bearer secret", `{"knowledge_id":"broken","query":"q"}`); out["error_msg"] != "Internal error." {
+		t.Errorf("Expected a fixed error message, got %v", out["error_msg"])
+	}
+	select {
+	case err := <-logged:
+		if err.Error() != "vector store unavailable" {
+			t.Errorf("Expected retriever error to be passed to the error handler, got %v", err)
+		}
+	default:
+		t.Error("Expected retriever error to be passed to the error handler")
+	}
+
+	// 非协议定义的错误同样以 JSON 返回
+	getResp, err := http.Get(server.URL + "/retrieval")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer getResp.Body.Close()
+	var getOut ExternalKnowledgeError
+	if err := json.NewDecoder(getResp.Body).Decode(&getOut); err != nil {
+		t.Fatal("Expected JSON error body:", err)
+	}
+	if getResp.StatusCode != http.StatusMethodNotAllowed || getOut.ErrorCode != ExternalKnowledgeErrorMethodNotAllowed {
+		t.Errorf("Expected 405 JSON error, got %d %+v", getResp.StatusCode, getOut)
+	}
+
+	ctx := context.Background()
+	check, err := CheckExternalKnowledgeAPIConnectivity(ctx, ExternalKnowledgeAPISettings{Endpoint: server.URL + "/", APIKey: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Authorized {
+		t.Errorf("Expected connectivity check to be authorized, got %+v", check)
+	}
+	check, err = CheckExternalKnowledgeAPIConnectivity(ctx, ExternalKnowledgeAPISettings{Endpoint: server.URL, APIKey: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Authorized || check.ErrorCode != ExternalKnowledgeErrorAuthFailed {
+		t.Errorf("Expected auth failure, got %+v", check)
+	}
+}
@@ -0,0 +1,120 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// UploadFile 上传文件
+// 上传的文件可在发送消息或调用工作流时以 local_file 方式引用，仅对当前终端用户可见
+func (a *appClient) UploadFile(ctx context.Context, req *UploadFileRequest) (*Response[UploadFileResponse], error) {
+	var resp = &UploadFileResponse{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetRetryCount(0). // 文件内容只能读取一次，不能重试
+		SetFileReader("file", req.Filename, req.File).
+		SetFormData(map[string]string{
+			"user": req.User,
+		}).
+		SetResult(&resp).
+		Post("/files/upload")
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[UploadFileResponse](response, resp), nil
+}
+
+// UploadFileRequest 上传文件请求
+type UploadFileRequest struct {
+	User     string    // 用户标识，必须和发送消息时的 user 一致
+	Filename string    // 文件名
+	File     io.Reader // 文件内容
+	Token    string    // 应用访问令牌
+}
+
+// UploadFileResponse 上传文件响应
+type UploadFileResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	Extension string `json:"extension"`
+	MimeType  string `json:"mime_type"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// FileType 文件类型
+type FileType string
+
+const (
+	FileTypeDocument FileType = "document" // 文档：TXT、MD、PDF、DOCX、XLSX、CSV 等
+	FileTypeImage    FileType = "image"    // 图片：JPG、PNG、GIF、WEBP、SVG
+	FileTypeAudio    FileType = "audio"    // 音频：MP3、M4A、WAV、WEBM、AMR
+	FileTypeVideo    FileType = "video"    // 视频：MP4、MOV、MPEG、MPGA
+	FileTypeCustom   FileType = "custom"   // 其他文件类型
+)
+
+// TransferMethod 文件传递方式
+type TransferMethod string
+
+const (
+	TransferMethodRemoteURL TransferMethod = "remote_url" // 远程地址
+	TransferMethodLocalFile TransferMethod = "local_file" // 通过 UploadFile 上传的文件
+)
+
+var fileTypesByExtension = map[string]FileType{}
+
+func init() {
+	for fileType, extensions := range map[FileType][]string{
+		FileTypeDocument: {"txt", "md", "markdown", "mdx", "pdf", "html", "htm", "xlsx", "xls", "doc", "docx", "csv", "eml", "msg", "pptx", "ppt", "xml", "epub"},
+		FileTypeImage:    {"jpg", "jpeg", "png", "gif", "webp", "svg"},
+		FileTypeAudio:    {"mp3", "m4a", "wav", "amr", "mpga"},
+		FileTypeVideo:    {"mp4", "mov", "mpeg", "webm"},
+	} {
+		for _, ext := range extensions {
+			fileTypesByExtension[ext] = fileType
+		}
+	}
+}
+
+// FileTypeOf 根据文件扩展名推断 Dify 的文件类型，未知扩展名返回 FileTypeCustom
+func FileTypeOf(filename string) FileType {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if fileType, ok := fileTypesByExtension[ext]; ok {
+		return fileType
+	}
+	return FileTypeCustom
+}
+
+// LocalFile 引用通过 UploadFile 上传的文件
+func LocalFile(fileType FileType, uploadFileID string) InputFile {
+	return InputFile{
+		Type:           fileType,
+		TransferMethod: TransferMethodLocalFile,
+		UploadFileID:   uploadFileID,
+	}
+}
+
+// RemoteFile 引用远程地址的文件
+func RemoteFile(fileType FileType, url string) InputFile {
+	return InputFile{
+		Type:           fileType,
+		TransferMethod: TransferMethodRemoteURL,
+		URL:            url,
+	}
+}
+
+// InputFile 作为消息附件或工作流文件类型输入变量的文件
+// 工作流的文件变量可直接把 InputFile（或 []InputFile）放入 CallWorkflowRequest.Inputs
+type InputFile struct {
+	Type           FileType       `json:"type"`                     // 文件类型
+	TransferMethod TransferMethod `json:"transfer_method"`          // 传递方式
+	URL            string         `json:"url,omitempty"`            // 文件地址（仅当传递方式为 remote_url 时）
+	UploadFileID   string         `json:"upload_file_id,omitempty"` // 上传文件 ID（仅当传递方式为 local_file 时）
+}
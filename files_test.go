@@ -0,0 +1,43 @@
+package dify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFileTypeOf(t *testing.T) {
+	cases := map[string]FileType{
+		"report.PDF":  FileTypeDocument,
+		"aaaa.docx":   FileTypeDocument,
+		"photo.jpeg":  FileTypeImage,
+		"voice.m4a":   FileTypeAudio,
+		"clip.mov":    FileTypeVideo,
+		"archive.zip": FileTypeCustom,
+		"noext":       FileTypeCustom,
+	}
+	for filename, want := range cases {
+		if got := FileTypeOf(filename); got != want {
+			t.Errorf("%s: expected %s, got %s", filename, want, got)
+		}
+	}
+}
+
+func TestInputFileInWorkflowInputs(t *testing.T) {
+	data, err := json.Marshal(&CallWorkflowRequest{
+		Inputs: map[string]interface{}{
+			"contract": LocalFile(FileTypeDocument, "upload-1"),
+			"images":   []InputFile{RemoteFile(FileTypeImage, "https://example.com/a.png")},
+		},
+		User: "test_user",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"inputs":{"contract":{"type":"document","transfer_method":"local_file","upload_file_id":"upload-1"},` +
+		`"images":[{"type":"image","transfer_method":"remote_url","url":"https://example.com/a.png"}]},` +
+		`"response_mode":"","user":"test_user"}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
@@ -0,0 +1,213 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"resty.dev/v3"
+	"strings"
+)
+
+// IndexingEstimate 预估文档的分段结果
+// 上传文件（或文本）后按 ProcessRule 进行分段预览，返回预览分段、分段总数和 embedding 消耗，不会创建文档
+func (c *client) IndexingEstimate(ctx context.Context, req *IndexingEstimateRequest) (*Response[IndexingEstimateResponse], error) {
+	if err := req.ProcessRule.Validate(req.DocForm); err != nil {
+		return nil, err
+	}
+	fileID, err := c.uploadEstimateFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.indexingEstimate(ctx, req, fileID, req.ProcessRule)
+}
+
+// CompareProcessRules 使用多组 ProcessRule 预估同一文档的分段结果，文档只上传一次
+// 返回结果与 rules 一一对应
+func (c *client) CompareProcessRules(ctx context.Context, req *IndexingEstimateRequest, rules []ProcessRule) ([]*IndexingEstimateResponse, error) {
+	// 先校验全部规则，避免上传后才发现规则无效
+	for i := range rules {
+		if err := rules[i].Validate(req.DocForm); err != nil {
+			return nil, fmt.Errorf("process rule %d: %w", i, err)
+		}
+	}
+	fileID, err := c.uploadEstimateFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	estimates := make([]*IndexingEstimateResponse, 0, len(rules))
+	for i, rule := range rules {
+		resp, err := c.indexingEstimate(ctx, req, fileID, rule)
+		if err != nil {
+			return estimates, fmt.Errorf("process rule %d: %w", i, err)
+		}
+		estimates = append(estimates, resp.Result)
+	}
+	return estimates, nil
+}
+
+// indexingEstimate 使用已上传的文件预估分段，调用方需先校验 rule
+func (c *client) indexingEstimate(ctx context.Context, req *IndexingEstimateRequest, fileID string, rule ProcessRule) (*Response[IndexingEstimateResponse], error) {
+	var resultErr error
+	var resp = &IndexingEstimateResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(&indexingEstimateInternalRequest{
+				InfoList: dataSourceInfoList{
+					DataSourceType: DataSourceTypeUploadFile,
+					FileInfoList:   &fileInfoList{FileIDs: []string{fileID}},
+				},
+				IndexingTechnique: req.IndexingTechnique,
+				ProcessRule:       rule,
+				DocForm:           req.DocForm,
+				DocLanguage:       req.DocLanguage,
+				DatasetID:         req.DatasetID,
+			}).
+			SetResult(&resp).
+			Post("/console/api/datasets/indexing-estimate")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to estimate indexing: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to estimate indexing with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[IndexingEstimateResponse](finalResponse, resp), nil
+}
+
+// uploadEstimateFile 通过控制台以流式 multipart 上传待预估的文件，Text 不为空时以文本文件上传
+// token 过期需要重试时，File 必须实现 io.Seeker 以便回到起始位置重新发送
+func (c *client) uploadEstimateFile(ctx context.Context, req *IndexingEstimateRequest) (string, error) {
+	filename := req.Filename
+	var body io.Reader = req.File
+	if req.Text != "" {
+		body = strings.NewReader(req.Text)
+		if filename == "" {
+			filename = "text.txt"
+		}
+	}
+	if body == nil {
+		return "", fmt.Errorf("indexing estimate requires File or Text")
+	}
+	seeker, _ := body.(io.Seeker)
+	var start int64
+	if seeker != nil {
+		var err error
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seeker = nil
+		}
+	}
+
+	var resultErr error
+	var resp = &UploadFileResponse{}
+	attempts := 0
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		if attempts++; attempts > 1 {
+			if seeker == nil {
+				resultErr = fmt.Errorf("failed to upload %s: cannot resend a file that is not an io.Seeker after refreshing the access token", filename)
+				return nil, resultErr
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				resultErr = fmt.Errorf("failed to rewind %s: %w", filename, err)
+				return nil, resultErr
+			}
+		}
+		response, err := c.postMultipartFile(
+			c.console().WithContext(ctx).SetQueryParam("source", "datasets"),
+			"/console/api/files/upload",
+			nil,
+			&documentFile{Filename: filename, Body: body},
+			&resp,
+		)
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to upload %s: %w", filename, err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to upload %s with status %d: %s", filename, response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if resultErr != nil {
+		return "", resultErr
+	}
+
+	return resp.ID, nil
+}
+
+// IndexingEstimateRequest 分段预估请求
+type IndexingEstimateRequest struct {
+	DatasetID         string    // 知识库ID（可选），指定后沿用知识库的 embedding 配置
+	Filename          string    // 文件名，决定服务端使用的文档解析方式
+	File              io.Reader // 文件内容，以流式上传；实现 io.Seeker 时 token 过期后可以重新发送
+	Text              string    // 文本内容，不为空时忽略 File
+	IndexingTechnique IndexingTechnique
+	DocForm           DocForm
+	DocLanguage       string
+	ProcessRule       ProcessRule
+}
+
+// indexingEstimateInternalRequest 内部使用的分段预估请求结构
+type indexingEstimateInternalRequest struct {
+	InfoList          dataSourceInfoList `json:"info_list"`
+	IndexingTechnique IndexingTechnique  `json:"indexing_technique"`
+	ProcessRule       ProcessRule        `json:"process_rule"`
+	DocForm           DocForm            `json:"doc_form,omitempty"`
+	DocLanguage       string             `json:"doc_language,omitempty"`
+	DatasetID         string             `json:"dataset_id,omitempty"`
+}
+
+// IndexingEstimateResponse 分段预估响应
+type IndexingEstimateResponse struct {
+	TotalSegments int              `json:"total_segments"`
+	Tokens        int64            `json:"tokens"`
+	TotalPrice    json.Number      `json:"total_price"` // 预估的 embedding 费用，服务端以十进制字符串返回
+	Currency      string           `json:"currency"`
+	Preview       []PreviewChunk   `json:"preview"`
+	QAPreview     []QAPreviewChunk `json:"qa_preview"` // Q&A 模式下的预览
+}
+
+// PreviewChunk 预览分段
+type PreviewChunk struct {
+	Content     string   `json:"content"`
+	ChildChunks []string `json:"child_chunks"` // parent-child 模式下的子分段
+}
+
+// QAPreviewChunk Q&A 模式下的预览分段
+type QAPreviewChunk struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
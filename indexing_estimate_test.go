@@ -0,0 +1,128 @@
+package dify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fzdwx/dify/difytest"
+)
+
+const estimateText = "第一章 总则\n本制度适用于全体员工。\n\n第二章 报销\n差旅费用需在一个月内报销。\n\n第三章 附则\n本制度自发布之日起施行。"
+
+func countUploads(server *difytest.Server) int {
+	n := 0
+	for _, r := range server.Requests() {
+		if r.Method == "POST" && r.Path == "/console/api/files/upload" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestIndexingEstimate(t *testing.T) {
+	c, _ := newTestClient(t)
+	resp, err := c.IndexingEstimate(context.Background(), &IndexingEstimateRequest{
+		Text:              estimateText,
+		IndexingTechnique: IndexingTechniqueHighQuality,
+		DocForm:           DocFormTextModel,
+		ProcessRule:       ProcessRule{Mode: ProcessModeAutomatic},
+	})
+	if err != nil {
+		t.Fatal("Failed to estimate indexing:", err)
+	}
+	estimate := resp.Result
+	if estimate.TotalSegments != 3 || len(estimate.Preview) != 3 {
+		t.Fatalf("Expected 3 segments, got %+v", estimate)
+	}
+	if !strings.HasPrefix(estimate.Preview[1].Content, "第二章") {
+		t.Errorf("Unexpected second segment '%s'", estimate.Preview[1].Content)
+	}
+	// total_price 以十进制字符串返回
+	price, err := estimate.TotalPrice.Float64()
+	if err != nil || price <= 0 || estimate.Currency != "USD" {
+		t.Errorf("Unexpected price %q %s: %v", estimate.TotalPrice, estimate.Currency, err)
+	}
+}
+
+func TestCompareProcessRules(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	req := &IndexingEstimateRequest{
+		Filename:          "policy.md",
+		File:              strings.NewReader(estimateText),
+		IndexingTechnique: IndexingTechniqueEconomy,
+		DocForm:           DocFormTextModel,
+	}
+	estimates, err := c.CompareProcessRules(ctx, req, []ProcessRule{
+		{Mode: ProcessModeAutomatic},
+		{Mode: ProcessModeManual, Rules: ProcessRules{Segmentation: Segmentation{Separator: `\n`, MaxTokens: 500}}},
+	})
+	if err != nil {
+		t.Fatal("Failed to compare process rules:", err)
+	}
+	if len(estimates) != 2 || estimates[0].TotalSegments != 3 || estimates[1].TotalSegments != 6 {
+		t.Fatalf("Unexpected estimates: %+v", estimates)
+	}
+	if n := countUploads(server); n != 1 {
+		t.Errorf("Expected the file to be uploaded once, got %d", n)
+	}
+}
+
+func TestIndexingEstimateInvalidRule(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	invalid := ProcessRule{Mode: ProcessModeManual, Rules: ProcessRules{Segmentation: Segmentation{Separator: `\n`}}}
+
+	if _, err := c.IndexingEstimate(ctx, &IndexingEstimateRequest{Text: estimateText, DocForm: DocFormTextModel, ProcessRule: invalid}); err == nil {
+		t.Error("Expected invalid rule error")
+	}
+	_, err := c.CompareProcessRules(ctx, &IndexingEstimateRequest{Text: estimateText, DocForm: DocFormTextModel},
+		[]ProcessRule{{Mode: ProcessModeAutomatic}, invalid})
+	if err == nil || !strings.Contains(err.Error(), "process rule 1") {
+		t.Errorf("Expected error for process rule 1, got %v", err)
+	}
+	if n := countUploads(server); n != 0 {
+		t.Errorf("Expected no upload for invalid rules, got %d", n)
+	}
+}
+
+func TestIndexingEstimateTokenRefresh(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	req := &IndexingEstimateRequest{
+		Filename:          "policy.md",
+		File:              io.MultiReader(strings.NewReader(estimateText)),
+		IndexingTechnique: IndexingTechniqueEconomy,
+		DocForm:           DocFormTextModel,
+		ProcessRule:       ProcessRule{Mode: ProcessModeAutomatic},
+	}
+
+	// 不可 Seek 的文件以流式上传
+	if _, err := c.IndexingEstimate(ctx, req); err != nil {
+		t.Fatal("Failed to estimate indexing from a stream:", err)
+	}
+
+	// 可 Seek 的文件在 token 刷新后回到起始位置重新上传
+	server.ExpireAccessTokens()
+	req.File = bytes.NewReader([]byte(estimateText))
+	resp, err := c.IndexingEstimate(ctx, req)
+	if err != nil {
+		t.Fatal("Failed to estimate indexing after token refresh:", err)
+	}
+	if resp.Result.TotalSegments != 3 {
+		t.Errorf("Expected the whole file to be uploaded, got %+v", resp.Result)
+	}
+	if n := countUploads(server); n != 3 {
+		t.Errorf("Expected the upload to be retried once, got %d uploads", n)
+	}
+
+	// 不可 Seek 的文件在 token 过期后无法重新发送
+	server.ExpireAccessTokens()
+	req.File = io.MultiReader(strings.NewReader(estimateText))
+	if _, err := c.IndexingEstimate(ctx, req); err == nil || !strings.Contains(err.Error(), "io.Seeker") {
+		t.Errorf("Expected a non-seekable retry error, got %v", err)
+	}
+}
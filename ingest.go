@@ -0,0 +1,516 @@
+package dify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SupportedDocumentExtensions 知识库支持通过文件创建文档的扩展名
+var SupportedDocumentExtensions = []string{
+	".txt", ".md", ".markdown", ".mdx", ".pdf", ".html", ".htm", ".xlsx", ".xls", ".doc", ".docx",
+	".csv", ".vtt", ".properties", ".xml", ".epub", ".ppt", ".pptx", ".eml", ".msg",
+}
+
+// IngestFiles 把目录中匹配的文件批量上传到知识库
+// 按 Concurrency 并发上传、按 RatePerSecond 限速，跳过不支持的扩展名，可选等待嵌入完成
+// 单个文件失败不会中断其他文件，失败信息记录在报告中；把报告作为 Previous 再次调用即可只重试失败的文件
+func (c *client) IngestFiles(ctx context.Context, req *IngestRequest) (*IngestReport, error) {
+	if req.DatasetID == "" {
+		return nil, fmt.Errorf("ingest requires a dataset ID")
+	}
+	if err := req.Document.ProcessRule.Validate(req.Document.DocForm); err != nil {
+		return nil, err
+	}
+	fsys := req.FS
+	if fsys == nil {
+		if req.Dir == "" {
+			return nil, fmt.Errorf("ingest requires a directory or fs.FS")
+		}
+		fsys = os.DirFS(req.Dir)
+	}
+	paths, err := req.matchFiles(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[string]IngestFileResult{}
+	if req.Previous != nil {
+		for _, result := range req.Previous.Files {
+			previous[result.Path] = result
+		}
+	}
+
+	report := &IngestReport{Files: make([]IngestFileResult, len(paths))}
+	var pending []int
+	for i, p := range paths {
+		prev, ok := previous[p]
+		switch {
+		case ok && prev.Succeeded():
+			report.Files[i] = prev
+		case !req.supported(p):
+			report.Files[i] = IngestFileResult{Path: p, Status: IngestStatusSkipped, Error: "unsupported file extension"}
+		default:
+			// 上次嵌入失败的文档需要先删除，避免重试后出现重复文档
+			report.Files[i] = IngestFileResult{Path: p, DocumentID: prev.DocumentID}
+			pending = append(pending, i)
+		}
+	}
+
+	var recovery *ingestRecovery
+	if len(pending) > 0 {
+		pendingPaths := make([]string, len(pending))
+		for j, i := range pending {
+			pendingPaths[j] = paths[i]
+		}
+		docs, err := c.listAllDocuments(ctx, req.DatasetID, "")
+		if err != nil {
+			return nil, err
+		}
+		recovery = newIngestRecovery(docs, pendingPaths)
+	}
+	req.run(len(pending), func(j int, limiter <-chan time.Time) {
+		c.ingestFile(ctx, req, fsys, limiter, &report.Files[pending[j]], "", recovery)
+	})
+	return report, nil
+}
+
+// run 以 Concurrency 并发执行 n 个任务，limiter 按 RatePerSecond 限速
+func (req *IngestRequest) run(n int, task func(i int, limiter <-chan time.Time)) {
+	var limiter <-chan time.Time
+	if req.RatePerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / req.RatePerSecond))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				task(i, limiter)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// ingestRecovery 上传前的文档快照，创建文档遇到 5xx 时用于判断服务端是否已经创建了文档
+type ingestRecovery struct {
+	existing map[string]bool // 上传前知识库中已有的文档ID
+	shared   map[string]bool // 多个待上传文件共用的文档名，无法判断文档属于哪个文件
+}
+
+// newIngestRecovery 根据上传 paths 之前知识库中的文档 docs 创建快照
+func newIngestRecovery(docs []Document, paths []string) *ingestRecovery {
+	recovery := &ingestRecovery{existing: map[string]bool{}, shared: map[string]bool{}}
+	for _, doc := range docs {
+		recovery.existing[doc.Id] = true
+	}
+	names := map[string]bool{}
+	for _, p := range paths {
+		name := path.Base(p)
+		if names[name] {
+			recovery.shared[name] = true
+		}
+		names[name] = true
+	}
+	return recovery
+}
+
+// ingestFile 上传单个文件并把结果写入 result
+// updateID 不为空时通过 UpdateByFile 更新该文档，文档已不存在时改为创建
+func (c *client) ingestFile(ctx context.Context, req *IngestRequest, fsys fs.FS, limiter <-chan time.Time, result *IngestFileResult, updateID string, recovery *ingestRecovery) {
+	fail := func(err error) {
+		result.Status = IngestStatusFailed
+		result.Error = err.Error()
+		result.Err = err
+	}
+
+	if result.DocumentID != "" {
+		resp, err := c.DeleteDocument(ctx, &DeleteDocumentRequest{DatasetID: req.DatasetID, DocumentID: result.DocumentID})
+		if err != nil {
+			fail(fmt.Errorf("failed to delete previous document: %w", err))
+			return
+		}
+		if resp.IsError() && resp.StatusCode() != 404 {
+			fail(fmt.Errorf("failed to delete previous document with status %d: %s", resp.StatusCode(), resp.Message))
+			return
+		}
+		result.DocumentID = ""
+	}
+
+	var created *CreateByFileResponse
+	var recovered *Document
+upload:
+	for attempt := 0; ; attempt++ {
+		if limiter != nil {
+			select {
+			case <-ctx.Done():
+				fail(ctx.Err())
+				return
+			case <-limiter:
+			}
+		}
+		resp, err := c.uploadIngestFile(ctx, req, fsys, result.Path, updateID)
+		if err == nil && updateID != "" && resp.StatusCode() == 404 {
+			updateID = ""
+			resp, err = c.uploadIngestFile(ctx, req, fsys, result.Path, "")
+		}
+		if err == nil && resp.IsSuccess() {
+			created = resp.Result
+			break
+		}
+
+		// 只重试网络错误、429 和 503；打开文件失败等本地错误不重试
+		var retryable bool
+		if err != nil {
+			var netErr net.Error
+			retryable = errors.As(err, &netErr)
+		} else {
+			err = fmt.Errorf("failed to upload with status %d: %s", resp.StatusCode(), resp.Message)
+			switch code := resp.StatusCode(); {
+			case code == 429 || code == 503:
+				retryable = true
+			case code >= 500 && updateID != "":
+				// 更新的是同一个文档，重试不会产生重复文档
+				retryable = true
+			case code >= 500 && recovery.shared[path.Base(result.Path)]:
+				// 同名文件不止一个，无法判断服务端创建的文档属于哪个文件，重试可能产生重复文档
+				fail(fmt.Errorf("%w (not retried: another file has the same name)", err))
+				return
+			case code >= 500:
+				// 服务端可能已经创建了文档，先查找上传前不存在的同名文档，避免重复上传
+				doc, lookupErr := c.findIngestedDocument(ctx, req.DatasetID, path.Base(result.Path), recovery)
+				if lookupErr != nil {
+					fail(fmt.Errorf("%w (failed to look up document: %v)", err, lookupErr))
+					return
+				}
+				if doc != nil {
+					recovered = doc
+					break upload
+				}
+				retryable = true
+			}
+		}
+		if !retryable || ctx.Err() != nil || attempt >= req.Retries {
+			fail(err)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			fail(ctx.Err())
+			return
+		case <-time.After(req.retryDelay(attempt, resp)):
+		}
+	}
+
+	if recovered != nil {
+		result.DocumentID = recovered.Id
+		result.Status = IngestStatusUploaded
+		if !req.WaitIndexing {
+			return
+		}
+		doc, err := c.waitDocumentIndexing(ctx, req, *recovered)
+		if err != nil {
+			fail(fmt.Errorf("failed to wait indexing: %w", err))
+			return
+		}
+		result.IndexingStatus = IndexingStatus(doc.IndexingStatus)
+		if result.IndexingStatus != IndexingStatusCompleted {
+			msg := string(result.IndexingStatus)
+			if doc.Error != nil {
+				msg = fmt.Sprint(doc.Error)
+			}
+			fail(fmt.Errorf("indexing failed: %s", msg))
+			return
+		}
+		result.Status = IngestStatusCompleted
+		return
+	}
+
+	result.DocumentID = created.Document.Id
+	result.Batch = created.Batch
+	result.Status = IngestStatusUploaded
+	if !req.WaitIndexing {
+		return
+	}
+
+	statuses, err := c.WaitIndexing(ctx, &GetIndexingStatusRequest{DatasetID: req.DatasetID, Batch: created.Batch}, req.PollInterval)
+	if err != nil {
+		fail(fmt.Errorf("failed to wait indexing: %w", err))
+		return
+	}
+	for _, status := range statuses {
+		if status.ID != result.DocumentID {
+			continue
+		}
+		result.IndexingStatus = status.IndexingStatus
+		if status.IndexingStatus != IndexingStatusCompleted {
+			msg := status.Error
+			if msg == "" {
+				msg = string(status.IndexingStatus)
+			}
+			fail(fmt.Errorf("indexing failed: %s", msg))
+			return
+		}
+	}
+	result.Status = IngestStatusCompleted
+}
+
+// retryDelay 返回第 attempt 次失败后的等待时间：从 RetryBackoff 开始按指数增长，最长 30 秒
+// 429 响应带有 Retry-After 时以服务端要求为准
+func (req *IngestRequest) retryDelay(attempt int, resp *Response[CreateByFileResponse]) time.Duration {
+	if resp != nil && resp.StatusCode() == 429 {
+		if d, ok := parseRetryAfter(resp.Header().Get("Retry-After")); ok {
+			return d
+		}
+	}
+	delay := req.RetryBackoff
+	if delay <= 0 {
+		delay = time.Second
+	}
+	for i := 0; i < attempt && delay < maxIngestRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxIngestRetryDelay {
+		delay = maxIngestRetryDelay
+	}
+	return delay
+}
+
+const maxIngestRetryDelay = 30 * time.Second
+
+// parseRetryAfter 解析以秒数或 HTTP 日期表示的 Retry-After
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// findIngestedDocument 查找上传请求出错前服务端可能已经创建的文档，只考虑上传前不存在的同名文档
+func (c *client) findIngestedDocument(ctx context.Context, datasetID, name string, recovery *ingestRecovery) (*Document, error) {
+	docs, err := c.findDocumentsByName(ctx, datasetID, name)
+	if err != nil {
+		return nil, err
+	}
+	var found *Document
+	for i, doc := range docs {
+		if !recovery.existing[doc.Id] && (found == nil || doc.CreatedAt > found.CreatedAt) {
+			found = &docs[i]
+		}
+	}
+	return found, nil
+}
+
+// waitDocumentIndexing 轮询文档列表直到 doc 嵌入结束，用于无法得知批次号的文档
+func (c *client) waitDocumentIndexing(ctx context.Context, req *IngestRequest, doc Document) (Document, error) {
+	interval := req.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for !IndexingStatus(doc.IndexingStatus).Finished() {
+		select {
+		case <-ctx.Done():
+			return doc, ctx.Err()
+		case <-ticker.C:
+		}
+		docs, err := c.findDocumentsByName(ctx, req.DatasetID, doc.Name)
+		if err != nil {
+			return doc, err
+		}
+		found := false
+		for _, latest := range docs {
+			if latest.Id == doc.Id {
+				doc, found = latest, true
+			}
+		}
+		if !found {
+			return doc, fmt.Errorf("document %s not found", doc.Id)
+		}
+	}
+	return doc, nil
+}
+
+// uploadIngestFile 每次尝试都重新打开文件，保证重试时上传完整内容
+func (c *client) uploadIngestFile(ctx context.Context, req *IngestRequest, fsys fs.FS, p string, updateID string) (*Response[CreateByFileResponse], error) {
+	file, err := fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if updateID != "" {
+		update := &UpdateByFileRequest{
+			DatasetID:   req.DatasetID,
+			DocumentID:  updateID,
+			Filename:    path.Base(p),
+			FileBody:    file,
+			Name:        path.Base(p),
+			DocForm:     req.Document.DocForm,
+			DocLanguage: req.Document.DocLanguage,
+		}
+		if req.Document.ProcessRule.Mode != "" {
+			rule := req.Document.ProcessRule
+			update.ProcessRule = &rule
+		}
+		return c.UpdateByFile(ctx, update)
+	}
+	doc := req.Document
+	doc.DatasetsID = req.DatasetID
+	doc.Filename = path.Base(p)
+	doc.FileBody = file
+	return c.CreateByFile(ctx, &doc)
+}
+
+// matchFiles 返回 fsys 中匹配 Patterns 的文件，按路径排序
+func (req *IngestRequest) matchFiles(fsys fs.FS) ([]string, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		matched, err := req.match(p)
+		if err != nil {
+			return err
+		}
+		if matched {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// match 不含 / 的模式匹配文件名，其余模式匹配相对路径
+func (req *IngestRequest) match(p string) (bool, error) {
+	if len(req.Patterns) == 0 {
+		return true, nil
+	}
+	for _, pattern := range req.Patterns {
+		name := p
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(p)
+		}
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (req *IngestRequest) supported(p string) bool {
+	extensions := req.Extensions
+	if len(extensions) == 0 {
+		extensions = SupportedDocumentExtensions
+	}
+	ext := strings.ToLower(path.Ext(p))
+	for _, e := range extensions {
+		if strings.ToLower(e) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// IngestRequest 批量上传文件请求
+type IngestRequest struct {
+	DatasetID     string              // 知识库ID
+	FS            fs.FS               // 文件来源，为空时使用 Dir
+	Dir           string              // 本地目录
+	Patterns      []string            // glob 模式（path.Match 语法），为空时匹配全部文件
+	Extensions    []string            // 允许的扩展名，为空时使用 SupportedDocumentExtensions
+	Concurrency   int                 // 并发上传数，默认为 4
+	RatePerSecond float64             // 每秒最多发起的上传数，为 0 时不限速
+	Retries       int                 // 网络错误、429 和 5xx 时的重试次数，创建文档遇到 503 以外的 5xx 时先查找服务端是否已创建文档
+	RetryBackoff  time.Duration       // 首次重试前的等待时间，之后每次翻倍（最长 30 秒），默认为 1 秒
+	Document      CreateByFileRequest // 文档设置模板，DatasetsID、Filename 和 FileBody 会被覆盖
+	WaitIndexing  bool                // 是否等待嵌入完成
+	PollInterval  time.Duration       // 查询嵌入状态的间隔，默认为 2 秒
+	Previous      *IngestReport       // 上次的报告，其中已成功的文件不会重复上传
+}
+
+// IngestStatus 单个文件的上传结果
+type IngestStatus string
+
+const (
+	IngestStatusUploaded  IngestStatus = "uploaded"  // 已上传，未等待嵌入
+	IngestStatusCompleted IngestStatus = "completed" // 已上传并完成嵌入
+	IngestStatusSkipped   IngestStatus = "skipped"   // 扩展名不受支持
+	IngestStatusFailed    IngestStatus = "failed"    // 上传或嵌入失败
+)
+
+// IngestReport 批量上传报告，可序列化保存后用于重试
+type IngestReport struct {
+	Files []IngestFileResult `json:"files"`
+}
+
+// Failed 返回失败的文件
+func (r *IngestReport) Failed() []IngestFileResult {
+	var failed []IngestFileResult
+	for _, result := range r.Files {
+		if result.Status == IngestStatusFailed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// IngestFileResult 单个文件的上传结果
+type IngestFileResult struct {
+	Path           string         `json:"path"` // 相对于 FS 根目录的路径
+	DocumentID     string         `json:"document_id,omitempty"`
+	Batch          string         `json:"batch,omitempty"`
+	Status         IngestStatus   `json:"status"`
+	IndexingStatus IndexingStatus `json:"indexing_status,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	Err            error          `json:"-"`
+}
+
+// Succeeded 文件已成功上传（如等待嵌入则已完成嵌入）
+func (r IngestFileResult) Succeeded() bool {
+	return r.Status == IngestStatusUploaded || r.Status == IngestStatusCompleted
+}
@@ -0,0 +1,260 @@
+package dify
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/fzdwx/dify/difytest"
+)
+
+func TestIngestFiles(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetResp, err := c.CreateEmptyDataset(ctx, &CreateEmptyDatasetRequest{Name: "ingest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datasetID := datasetResp.Result.ID
+	fsys := fstest.MapFS{
+		"a.txt":       {Data: []byte("alpha")},
+		"b.md":        {Data: []byte("# beta")},
+		"logo.png":    {Data: []byte("png")},
+		"sub/c.csv":   {Data: []byte("q,a\n")},
+		"sub/d.txt":   {Data: []byte("delta")},
+		"other/e.pdf": {Data: []byte("%PDF")},
+	}
+	req := &IngestRequest{
+		DatasetID:    datasetID,
+		FS:           fsys,
+		Patterns:     []string{"*.txt", "*.md", "*.png", "sub/*.csv"},
+		Concurrency:  1,
+		Retries:      1,
+		RetryBackoff: 10 * time.Millisecond,
+		Document:     CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+		WaitIndexing: true,
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	// 第一个文件先遇到可重试的 503，随后遇到不可重试的 400
+	server.FailNext(http.MethodPost, "/v1/datasets/"+datasetID+"/document/create-by-file", http.StatusServiceUnavailable)
+	server.FailNext(http.MethodPost, "/v1/datasets/"+datasetID+"/document/create-by-file", http.StatusBadRequest)
+	report, err := c.IngestFiles(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]IngestStatus{
+		"a.txt":     IngestStatusFailed,
+		"b.md":      IngestStatusCompleted,
+		"logo.png":  IngestStatusSkipped,
+		"sub/c.csv": IngestStatusCompleted,
+		"sub/d.txt": IngestStatusCompleted,
+	}
+	if len(report.Files) != len(want) {
+		t.Fatalf("Expected %d files, got %+v", len(want), report.Files)
+	}
+	for _, result := range report.Files {
+		if result.Status != want[result.Path] {
+			t.Errorf("%s: expected %s, got %s (%s)", result.Path, want[result.Path], result.Status, result.Error)
+		}
+		if result.Status == IngestStatusCompleted && (result.DocumentID == "" || result.Batch == "") {
+			t.Errorf("%s: missing document ID or batch", result.Path)
+		}
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].Err == nil {
+		t.Fatalf("Expected one failed file with error, got %+v", failed)
+	}
+
+	req.Previous = report
+	retried, err := c.IngestFiles(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed := retried.Failed(); len(failed) != 0 {
+		t.Fatalf("Expected retry to succeed, got %+v", failed)
+	}
+	if docs := server.Documents(datasetID); len(docs) != 4 {
+		t.Errorf("Expected 4 documents without duplicates, got %d", len(docs))
+	}
+	for i, result := range retried.Files {
+		if result.Path != "a.txt" && result.DocumentID != report.Files[i].DocumentID {
+			t.Errorf("%s: expected previous document to be kept", result.Path)
+		}
+	}
+}
+
+func TestIngestFilesReplacesFailedDocument(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetResp, err := c.CreateEmptyDataset(ctx, &CreateEmptyDatasetRequest{Name: "ingest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datasetID := datasetResp.Result.ID
+	req := &IngestRequest{
+		DatasetID: datasetID,
+		FS:        fstest.MapFS{"a.txt": {Data: []byte("alpha")}},
+		Document:  CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+	}
+	first, err := c.IngestFiles(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 模拟嵌入失败的文档，重试时应删除旧文档后重新上传
+	first.Files[0].Status = IngestStatusFailed
+	req.Previous = first
+	second, err := c.IngestFiles(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := server.Documents(datasetID)
+	if len(docs) != 1 || docs[0].ID != second.Files[0].DocumentID || docs[0].ID == first.Files[0].DocumentID {
+		t.Errorf("Expected failed document to be replaced, got %+v", docs)
+	}
+}
+
+func TestIngestFilesRetryAfter(t *testing.T) {
+	c, server := newTestClient(t)
+	datasetID := newTestDataset(t, c)
+	// 等待时间以 Retry-After 为准，否则会按 RetryBackoff 等待到超时
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Fail(difytest.Failure{
+		Method: http.MethodPost,
+		Path:   "/v1/datasets/" + datasetID + "/document/create-by-file",
+		Status: http.StatusTooManyRequests,
+		Header: http.Header{"Retry-After": {"0"}},
+		Times:  1,
+	})
+	report, err := c.IngestFiles(ctx, &IngestRequest{
+		DatasetID:    datasetID,
+		FS:           fstest.MapFS{"a.txt": {Data: []byte("alpha")}},
+		Retries:      1,
+		RetryBackoff: time.Hour,
+		Document:     CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed := report.Failed(); len(failed) != 0 {
+		t.Fatalf("Expected retry after 429 to succeed, got %+v", failed)
+	}
+}
+
+func TestIngestFilesServerErrorDoesNotDuplicate(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetID := newTestDataset(t, c)
+	createPath := "/v1/datasets/" + datasetID + "/document/create-by-file"
+	req := &IngestRequest{
+		DatasetID:    datasetID,
+		FS:           fstest.MapFS{"a.txt": {Data: []byte("alpha")}},
+		Retries:      2,
+		RetryBackoff: 10 * time.Millisecond,
+		Document:     CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+		WaitIndexing: true,
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	// 服务端未创建文档就返回 500：查找不到同名文档，重新上传
+	server.FailNext(http.MethodPost, createPath, http.StatusInternalServerError)
+	first, err := c.IngestFiles(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Files[0].Status != IngestStatusCompleted {
+		t.Fatalf("Expected upload to be retried, got %+v", first.Files[0])
+	}
+
+	// 服务端已经创建文档但响应 504：应沿用本次创建的文档，不再上传，也不能误认第一次上传的文档
+	server.Fail(difytest.Failure{Method: http.MethodPost, Path: createPath, Status: http.StatusGatewayTimeout, Apply: true, Times: 1})
+	second, err := c.IngestFiles(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := server.Documents(datasetID)
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 documents without duplicates, got %d", len(docs))
+	}
+	result := second.Files[0]
+	if result.Status != IngestStatusCompleted || result.DocumentID != docs[1].ID || result.IndexingStatus != IndexingStatusCompleted {
+		t.Errorf("Expected created document %s to be recovered, got %+v", docs[1].ID, result)
+	}
+	uploads := 0
+	for _, r := range server.Requests() {
+		if r.Method == http.MethodPost && r.Path == createPath {
+			uploads++
+		}
+	}
+	if uploads != 3 {
+		t.Errorf("Expected 3 uploads, got %d", uploads)
+	}
+}
+
+func TestIngestFilesServerErrorWithSharedName(t *testing.T) {
+	c, server := newTestClient(t)
+	datasetID := newTestDataset(t, c)
+	createPath := "/v1/datasets/" + datasetID + "/document/create-by-file"
+	// 两个文件同名，无法判断服务端创建的文档属于哪个文件，出错的文件不重试也不沿用文档
+	server.Fail(difytest.Failure{Method: http.MethodPost, Path: createPath, Status: http.StatusInternalServerError, Apply: true, Times: 1})
+	report, err := c.IngestFiles(context.Background(), &IngestRequest{
+		DatasetID:    datasetID,
+		FS:           fstest.MapFS{"a/readme.md": {Data: []byte("a")}, "b/readme.md": {Data: []byte("b")}},
+		Concurrency:  1,
+		Retries:      2,
+		RetryBackoff: 10 * time.Millisecond,
+		Document:     CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := report.Files[0], report.Files[1]
+	if a.Status != IngestStatusFailed || a.DocumentID != "" {
+		t.Errorf("Expected a/readme.md to fail without recovering a document, got %+v", a)
+	}
+	docs := server.Documents(datasetID)
+	if b.Status != IngestStatusUploaded || len(docs) != 2 || b.DocumentID != docs[1].ID {
+		t.Errorf("Expected b/readme.md to be uploaded as its own document, got %+v (%d documents)", b, len(docs))
+	}
+}
+
+// openFailFS 打开文件总是失败，记录打开次数
+type openFailFS struct {
+	fstest.MapFS
+	opens atomic.Int32
+}
+
+func (f *openFailFS) Open(name string) (fs.File, error) {
+	if name == "." {
+		return f.MapFS.Open(name)
+	}
+	f.opens.Add(1)
+	return nil, errors.New("permission denied")
+}
+
+func TestIngestFilesDoesNotRetryLocalErrors(t *testing.T) {
+	c, _ := newTestClient(t)
+	datasetID := newTestDataset(t, c)
+	fsys := &openFailFS{MapFS: fstest.MapFS{"a.txt": {Data: []byte("alpha")}}}
+	report, err := c.IngestFiles(context.Background(), &IngestRequest{
+		DatasetID:    datasetID,
+		FS:           fsys,
+		Retries:      3,
+		RetryBackoff: 10 * time.Millisecond,
+		Document:     CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Files[0].Status != IngestStatusFailed {
+		t.Fatalf("Expected open error to fail the file, got %+v", report.Files[0])
+	}
+	if opens := fsys.opens.Load(); opens != 1 {
+		t.Errorf("Expected local error not to be retried, got %d opens", opens)
+	}
+}
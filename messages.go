@@ -0,0 +1,146 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// SendMessageFeedback 消息反馈
+// 对终端用户的消息进行点赞、点踩，Rating 为空时撤销已有反馈
+func (a *appClient) SendMessageFeedback(ctx context.Context, req *SendMessageFeedbackRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post(fmt.Sprintf("/messages/%s/feedbacks", req.MessageID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message feedback: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// GetSuggestedQuestions 获取下一轮建议问题列表
+func (a *appClient) GetSuggestedQuestions(ctx context.Context, req *GetSuggestedQuestionsRequest) (*Response[GetSuggestedQuestionsResponse], error) {
+	var resp = &GetSuggestedQuestionsResponse{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetQueryParam("user", req.User).
+		SetResult(&resp).
+		Get(fmt.Sprintf("/messages/%s/suggested", req.MessageID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggested questions: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[GetSuggestedQuestionsResponse](response, resp), nil
+}
+
+// ListAppFeedbacks 分页获取应用的消息反馈列表
+func (a *appClient) ListAppFeedbacks(ctx context.Context, req *ListAppFeedbacksRequest) (*Response[ListAppFeedbacksResponse], error) {
+	var resp = &ListAppFeedbacksResponse{}
+	request := a.request().
+		WithContext(ctx).
+		SetResult(&resp)
+	if req.Page > 0 {
+		request.SetQueryParam("page", strconv.Itoa(req.Page))
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	response, err := request.Get("/app/feedbacks")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list app feedbacks: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ListAppFeedbacksResponse](response, resp), nil
+}
+
+// FeedbackRating 消息反馈评分
+type FeedbackRating string
+
+const (
+	FeedbackRatingLike    FeedbackRating = "like"    // 点赞
+	FeedbackRatingDislike FeedbackRating = "dislike" // 点踩
+	FeedbackRatingNone    FeedbackRating = ""        // 撤销反馈，序列化为 null
+)
+
+// MarshalJSON 将 FeedbackRatingNone 序列化为 null
+func (r FeedbackRating) MarshalJSON() ([]byte, error) {
+	if r == FeedbackRatingNone {
+		return []byte("null"), nil
+	}
+	return json.Marshal(string(r))
+}
+
+// UnmarshalJSON 将 null 反序列化为 FeedbackRatingNone
+func (r *FeedbackRating) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*r = FeedbackRatingNone
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*r = FeedbackRating(s)
+	return nil
+}
+
+// SendMessageFeedbackRequest 消息反馈请求
+type SendMessageFeedbackRequest struct {
+	MessageID string         `json:"-"`                 // 消息ID，不包含在JSON中
+	Token     string         `json:"-"`                 // 应用访问令牌
+	Rating    FeedbackRating `json:"rating"`            // 点赞 like，点踩 dislike，撤销 FeedbackRatingNone
+	User      string         `json:"user"`              // 用户标识
+	Content   string         `json:"content,omitempty"` // 反馈的具体信息
+}
+
+// GetSuggestedQuestionsRequest 获取建议问题请求
+type GetSuggestedQuestionsRequest struct {
+	MessageID string // 消息ID
+	User      string // 用户标识
+	Token     string // 应用访问令牌
+}
+
+// GetSuggestedQuestionsResponse 建议问题响应
+type GetSuggestedQuestionsResponse struct {
+	Result string   `json:"result"`
+	Data   []string `json:"data"`
+}
+
+// ListAppFeedbacksRequest 获取应用反馈列表请求
+type ListAppFeedbacksRequest struct {
+	Page  int    // 页码，默认 1
+	Limit int    // 每页数量，默认 20
+	Token string // 应用访问令牌
+}
+
+// ListAppFeedbacksResponse 应用反馈列表响应
+type ListAppFeedbacksResponse struct {
+	Data []AppFeedback `json:"data"`
+}
+
+// AppFeedback 应用的一条消息反馈
+type AppFeedback struct {
+	ID             string         `json:"id"`
+	AppID          string         `json:"app_id"`
+	ConversationID string         `json:"conversation_id"`
+	MessageID      string         `json:"message_id"`
+	Rating         FeedbackRating `json:"rating"`
+	Content        string         `json:"content"`
+	FromSource     string         `json:"from_source"` // user / admin
+	FromEndUserID  string         `json:"from_end_user_id"`
+	FromAccountID  string         `json:"from_account_id"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package dify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSendMessageFeedbackRequestRating(t *testing.T) {
+	cases := map[FeedbackRating]string{
+		FeedbackRatingLike:    `{"rating":"like","user":"u"}`,
+		FeedbackRatingDislike: `{"rating":"dislike","user":"u"}`,
+		FeedbackRatingNone:    `{"rating":null,"user":"u"}`,
+	}
+	for rating, want := range cases {
+		data, err := json.Marshal(&SendMessageFeedbackRequest{MessageID: "m", Token: "t", Rating: rating, User: "u"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("rating %q: expected %s, got %s", rating, want, data)
+		}
+	}
+
+	var feedback AppFeedback
+	if err := json.Unmarshal([]byte(`{"rating":null}`), &feedback); err != nil {
+		t.Fatal(err)
+	}
+	if feedback.Rating != FeedbackRatingNone {
+		t.Errorf("Expected empty rating, got '%s'", feedback.Rating)
+	}
+}
@@ -0,0 +1,286 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateMetadataField 新增知识库元数据字段
+func (c *client) CreateMetadataField(ctx context.Context, req *CreateMetadataFieldRequest) (*Response[MetadataField], error) {
+	var resp = &MetadataField{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post(fmt.Sprintf("/datasets/%s/metadata", req.DatasetID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[MetadataField](response, resp), nil
+}
+
+// UpdateMetadataField 重命名知识库元数据字段
+func (c *client) UpdateMetadataField(ctx context.Context, req *UpdateMetadataFieldRequest) (*Response[MetadataField], error) {
+	var resp = &MetadataField{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Patch(fmt.Sprintf("/datasets/%s/metadata/%s", req.DatasetID, req.MetadataID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[MetadataField](response, resp), nil
+}
+
+// DeleteMetadataField 删除知识库元数据字段
+func (c *client) DeleteMetadataField(ctx context.Context, req *DeleteMetadataFieldRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp).
+		Delete(fmt.Sprintf("/datasets/%s/metadata/%s", req.DatasetID, req.MetadataID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// ListMetadataFields 获取知识库的元数据字段列表
+func (c *client) ListMetadataFields(ctx context.Context, req *ListMetadataFieldsRequest) (*Response[ListMetadataFieldsResponse], error) {
+	var resp = &ListMetadataFieldsResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get(fmt.Sprintf("/datasets/%s/metadata", req.DatasetID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ListMetadataFieldsResponse](response, resp), nil
+}
+
+// ToggleBuiltInMetadataFields 启用或禁用知识库的内置元数据字段
+func (c *client) ToggleBuiltInMetadataFields(ctx context.Context, req *ToggleBuiltInMetadataFieldsRequest) (*Response[ResultResponse], error) {
+	action := "disable"
+	if req.Enabled {
+		action = "enable"
+	}
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp).
+		Post(fmt.Sprintf("/datasets/%s/metadata/built-in/%s", req.DatasetID, action))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// UpdateDocumentsMetadata 批量设置文档的元数据值
+// 每个文档的 MetadataList 会整体替换该文档已有的自定义元数据
+func (c *client) UpdateDocumentsMetadata(ctx context.Context, req *UpdateDocumentsMetadataRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post(fmt.Sprintf("/datasets/%s/documents/metadata", req.DatasetID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// MetadataType 元数据字段类型
+type MetadataType string
+
+const (
+	MetadataTypeString MetadataType = "string"
+	MetadataTypeNumber MetadataType = "number"
+	MetadataTypeTime   MetadataType = "time" // Unix 时间戳（秒）
+)
+
+// 内置元数据字段名称
+const (
+	BuiltInMetadataDocumentName   = "document_name"
+	BuiltInMetadataUploader       = "uploader"
+	BuiltInMetadataUploadDate     = "upload_date"
+	BuiltInMetadataLastUpdateDate = "last_update_date"
+	BuiltInMetadataSource         = "source"
+)
+
+// MetadataField 元数据字段定义
+type MetadataField struct {
+	ID    string       `json:"id"`
+	Name  string       `json:"name"`
+	Type  MetadataType `json:"type"`
+	Count int          `json:"count"` // 使用该字段的文档数量（仅列表接口返回）
+}
+
+// CreateMetadataFieldRequest 新增元数据字段请求
+type CreateMetadataFieldRequest struct {
+	DatasetID string       `json:"-"` // 知识库ID，不包含在JSON中
+	Type      MetadataType `json:"type"`
+	Name      string       `json:"name"`
+}
+
+// UpdateMetadataFieldRequest 重命名元数据字段请求
+type UpdateMetadataFieldRequest struct {
+	DatasetID  string `json:"-"` // 知识库ID，不包含在JSON中
+	MetadataID string `json:"-"` // 元数据字段ID，不包含在JSON中
+	Name       string `json:"name"`
+}
+
+// DeleteMetadataFieldRequest 删除元数据字段请求
+type DeleteMetadataFieldRequest struct {
+	DatasetID  string // 知识库ID
+	MetadataID string // 元数据字段ID
+}
+
+// ListMetadataFieldsRequest 获取元数据字段列表请求
+type ListMetadataFieldsRequest struct {
+	DatasetID string // 知识库ID
+}
+
+// ListMetadataFieldsResponse 元数据字段列表响应
+type ListMetadataFieldsResponse struct {
+	DocMetadata         []MetadataField `json:"doc_metadata"`
+	BuiltInFieldEnabled bool            `json:"built_in_field_enabled"`
+}
+
+// ToggleBuiltInMetadataFieldsRequest 启用/禁用内置元数据字段请求
+type ToggleBuiltInMetadataFieldsRequest struct {
+	DatasetID string // 知识库ID
+	Enabled   bool   // true 启用，false 禁用
+}
+
+// UpdateDocumentsMetadataRequest 批量设置文档元数据请求
+type UpdateDocumentsMetadataRequest struct {
+	DatasetID     string                   `json:"-"` // 知识库ID，不包含在JSON中
+	OperationData []DocumentMetadataUpdate `json:"operation_data"`
+}
+
+// DocumentMetadataUpdate 单个文档的元数据值
+type DocumentMetadataUpdate struct {
+	DocumentID   string          `json:"document_id"`
+	MetadataList []MetadataValue `json:"metadata_list"`
+}
+
+// MetadataValue 元数据字段的值
+type MetadataValue struct {
+	ID    string      `json:"id"`   // 元数据字段ID
+	Name  string      `json:"name"` // 元数据字段名称
+	Value interface{} `json:"value"`
+}
+
+// LogicalOperator 元数据过滤条件之间的逻辑关系
+type LogicalOperator string
+
+const (
+	LogicalOperatorAnd LogicalOperator = "and"
+	LogicalOperatorOr  LogicalOperator = "or"
+)
+
+// ComparisonOperator 元数据过滤的比较运算符
+type ComparisonOperator string
+
+const (
+	// 字符串
+	ComparisonOperatorContains    ComparisonOperator = "contains"
+	ComparisonOperatorNotContains ComparisonOperator = "not contains"
+	ComparisonOperatorStartWith   ComparisonOperator = "start with"
+	ComparisonOperatorEndWith     ComparisonOperator = "end with"
+	ComparisonOperatorIs          ComparisonOperator = "is"
+	ComparisonOperatorIsNot       ComparisonOperator = "is not"
+	ComparisonOperatorEmpty       ComparisonOperator = "empty"
+	ComparisonOperatorNotEmpty    ComparisonOperator = "not empty"
+	ComparisonOperatorIn          ComparisonOperator = "in"
+	ComparisonOperatorNotIn       ComparisonOperator = "not in"
+
+	// 数字
+	ComparisonOperatorEqual        ComparisonOperator = "="
+	ComparisonOperatorNotEqual     ComparisonOperator = "≠"
+	ComparisonOperatorGreaterThan  ComparisonOperator = ">"
+	ComparisonOperatorLessThan     ComparisonOperator = "<"
+	ComparisonOperatorGreaterEqual ComparisonOperator = "≥"
+	ComparisonOperatorLessEqual    ComparisonOperator = "≤"
+
+	// 时间
+	ComparisonOperatorBefore ComparisonOperator = "before"
+	ComparisonOperatorAfter  ComparisonOperator = "after"
+)
+
+var comparisonOperators = map[ComparisonOperator]bool{
+	ComparisonOperatorContains: true, ComparisonOperatorNotContains: true,
+	ComparisonOperatorStartWith: true, ComparisonOperatorEndWith: true,
+	ComparisonOperatorIs: true, ComparisonOperatorIsNot: true,
+	ComparisonOperatorEmpty: true, ComparisonOperatorNotEmpty: true,
+	ComparisonOperatorIn: true, ComparisonOperatorNotIn: true,
+	ComparisonOperatorEqual: true, ComparisonOperatorNotEqual: true,
+	ComparisonOperatorGreaterThan: true, ComparisonOperatorLessThan: true,
+	ComparisonOperatorGreaterEqual: true, ComparisonOperatorLessEqual: true,
+	ComparisonOperatorBefore: true, ComparisonOperatorAfter: true,
+}
+
+// MetadataFilteringMode 应用/工作流中知识检索的元数据过滤模式
+type MetadataFilteringMode string
+
+const (
+	MetadataFilteringModeDisabled  MetadataFilteringMode = "disabled"
+	MetadataFilteringModeAutomatic MetadataFilteringMode = "automatic" // 由模型根据问题生成过滤条件
+	MetadataFilteringModeManual    MetadataFilteringMode = "manual"    // 使用配置的过滤条件
+)
+
+// MetadataFilteringConditions 元数据过滤表达式
+type MetadataFilteringConditions struct {
+	LogicalOperator LogicalOperator     `json:"logical_operator"`
+	Conditions      []MetadataCondition `json:"conditions"`
+}
+
+// MetadataCondition 单个元数据过滤条件
+type MetadataCondition struct {
+	Name               string             `json:"name"` // 元数据字段名称
+	ComparisonOperator ComparisonOperator `json:"comparison_operator"`
+	Value              interface{}        `json:"value,omitempty"` // empty / not empty 时不需要
+}
+
+// MetadataAnd 所有条件都满足
+func MetadataAnd(conditions ...MetadataCondition) *MetadataFilteringConditions {
+	return &MetadataFilteringConditions{LogicalOperator: LogicalOperatorAnd, Conditions: conditions}
+}
+
+// MetadataOr 任一条件满足
+func MetadataOr(conditions ...MetadataCondition) *MetadataFilteringConditions {
+	return &MetadataFilteringConditions{LogicalOperator: LogicalOperatorOr, Conditions: conditions}
+}
+
+// MetadataWhere 创建过滤条件，例如 MetadataWhere("year", ComparisonOperatorEqual, 2024)
+func MetadataWhere(name string, operator ComparisonOperator, value interface{}) MetadataCondition {
+	return MetadataCondition{Name: name, ComparisonOperator: operator, Value: value}
+}
+
+// Validate 校验过滤表达式
+func (f *MetadataFilteringConditions) Validate() error {
+	if f.LogicalOperator != LogicalOperatorAnd && f.LogicalOperator != LogicalOperatorOr {
+		return fmt.Errorf("invalid metadata logical operator %q", f.LogicalOperator)
+	}
+	if len(f.Conditions) == 0 {
+		return fmt.Errorf("metadata filtering conditions are empty")
+	}
+	for _, condition := range f.Conditions {
+		if condition.Name == "" {
+			return fmt.Errorf("metadata condition name is required")
+		}
+		if !comparisonOperators[condition.ComparisonOperator] {
+			return fmt.Errorf("invalid comparison operator %q for metadata %s", condition.ComparisonOperator, condition.Name)
+		}
+		needsValue := condition.ComparisonOperator != ComparisonOperatorEmpty && condition.ComparisonOperator != ComparisonOperatorNotEmpty
+		if needsValue && condition.Value == nil {
+			return fmt.Errorf("metadata condition %s %s requires a value", condition.Name, condition.ComparisonOperator)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMetadataFilteringConditions(t *testing.T) {
+	filter := MetadataAnd(
+		MetadataWhere("tenant", ComparisonOperatorIs, "acme"),
+		MetadataWhere("year", ComparisonOperatorEqual, 2024),
+		MetadataCondition{Name: "reviewer", ComparisonOperator: ComparisonOperatorNotEmpty},
+	)
+	if err := filter.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(&RetrieveRequest{
+		DatasetID: "d1",
+		Query:     "数据安全",
+		RetrievalModel: &RetrievalModel{
+			SearchMethod:                RetrievalModelSearchMethodSemanticSearch,
+			TopK:                        3,
+			MetadataFilteringConditions: filter,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"query":"数据安全","retrieval_model":{"search_method":"semantic_search","reranking_enable":false,` +
+		`"reranking_model":{"reranking_provider_name":"","reranking_model_name":""},"top_k":3,"score_threshold_enabled":false,"score_threshold":0,` +
+		`"metadata_filtering_conditions":{"logical_operator":"and","conditions":[{"name":"tenant","comparison_operator":"is","value":"acme"},` +
+		`{"name":"year","comparison_operator":"=","value":2024},{"name":"reviewer","comparison_operator":"not empty"}]}}}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	invalid := []*MetadataFilteringConditions{
+		{LogicalOperator: "xor", Conditions: filter.Conditions},
+		MetadataOr(),
+		MetadataOr(MetadataWhere("year", "between", 2024)),
+		MetadataOr(MetadataWhere("year", ComparisonOperatorGreaterThan, nil)),
+	}
+	for _, f := range invalid {
+		if err := f.Validate(); err == nil {
+			t.Errorf("Expected validation error for %+v", f)
+		}
+	}
+}
+
+func TestMetadataFields(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetID := newTestDataset(t, c)
+
+	tenant, err := c.CreateMetadataField(ctx, &CreateMetadataFieldRequest{DatasetID: datasetID, Type: MetadataTypeString, Name: "tenant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant.StatusCode() != http.StatusCreated || tenant.Result.ID == "" || tenant.Result.Type != MetadataTypeString {
+		t.Fatalf("Unexpected created field %d %+v", tenant.StatusCode(), tenant.Result)
+	}
+	year, err := c.CreateMetadataField(ctx, &CreateMetadataFieldRequest{DatasetID: datasetID, Type: MetadataTypeNumber, Name: "year"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 类型无效、与内置字段或已有字段重名时返回 400
+	for _, req := range []CreateMetadataFieldRequest{
+		{DatasetID: datasetID, Type: "bool", Name: "flag"},
+		{DatasetID: datasetID, Type: MetadataTypeString, Name: BuiltInMetadataSource},
+		{DatasetID: datasetID, Type: MetadataTypeString, Name: "tenant"},
+	} {
+		resp, err := c.CreateMetadataField(ctx, &req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode() != http.StatusBadRequest || resp.Code != "invalid_param" {
+			t.Errorf("%+v: expected 400 invalid_param, got %d %s", req, resp.StatusCode(), resp.Code)
+		}
+	}
+
+	renamed, err := c.UpdateMetadataField(ctx, &UpdateMetadataFieldRequest{DatasetID: datasetID, MetadataID: tenant.Result.ID, Name: "customer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renamed.IsSuccess() || renamed.Result.Name != "customer" || renamed.Result.ID != tenant.Result.ID {
+		t.Errorf("Unexpected renamed field %+v", renamed.Result)
+	}
+	missing, err := c.UpdateMetadataField(ctx, &UpdateMetadataFieldRequest{DatasetID: datasetID, MetadataID: "missing", Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing.StatusCode() != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing field, got %d", missing.StatusCode())
+	}
+
+	for _, enabled := range []bool{true, false} {
+		resp, err := c.ToggleBuiltInMetadataFields(ctx, &ToggleBuiltInMetadataFieldsRequest{DatasetID: datasetID, Enabled: enabled})
+		if err != nil {
+			t.Fatal(err)
+		}
+		list, err := c.ListMetadataFields(ctx, &ListMetadataFieldsRequest{DatasetID: datasetID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.IsSuccess() || list.Result.BuiltInFieldEnabled != enabled {
+			t.Errorf("Expected built-in fields enabled=%v, got %+v", enabled, list.Result)
+		}
+	}
+	var toggled []string
+	for _, r := range server.Requests() {
+		if strings.Contains(r.Path, "/metadata/built-in/") {
+			toggled = append(toggled, r.Path[strings.LastIndex(r.Path, "/")+1:])
+		}
+	}
+	if !reflect.DeepEqual(toggled, []string{"enable", "disable"}) {
+		t.Errorf("Expected enable then disable actions, got %v", toggled)
+	}
+
+	deleted, err := c.DeleteMetadataField(ctx, &DeleteMetadataFieldRequest{DatasetID: datasetID, MetadataID: year.Result.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.StatusCode() != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", deleted.StatusCode())
+	}
+	if deleted, err = c.DeleteMetadataField(ctx, &DeleteMetadataFieldRequest{DatasetID: datasetID, MetadataID: year.Result.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if deleted.StatusCode() != http.StatusNotFound {
+		t.Errorf("Expected 404 when deleting twice, got %d", deleted.StatusCode())
+	}
+	list, err := c.ListMetadataFields(ctx, &ListMetadataFieldsRequest{DatasetID: datasetID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Result.DocMetadata) != 1 || list.Result.DocMetadata[0].Name != "customer" {
+		t.Errorf("Expected only the renamed field to remain, got %+v", list.Result.DocMetadata)
+	}
+}
+
+func TestUpdateDocumentsMetadata(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetID := newTestDataset(t, c)
+	doc, err := c.CreateByFile(ctx, &CreateByFileRequest{
+		DatasetsID:        datasetID,
+		Filename:          "a.txt",
+		FileBody:          strings.NewReader("alpha"),
+		IndexingTechnique: IndexingTechniqueEconomy,
+		DocForm:           DocFormTextModel,
+		ProcessRule:       ProcessRule{Mode: ProcessModeAutomatic},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	field, err := c.CreateMetadataField(ctx, &CreateMetadataFieldRequest{DatasetID: datasetID, Type: MetadataTypeNumber, Name: "year"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(documentID, fieldID string) *Response[ResultResponse] {
+		t.Helper()
+		resp, err := c.UpdateDocumentsMetadata(ctx, &UpdateDocumentsMetadataRequest{
+			DatasetID: datasetID,
+			OperationData: []DocumentMetadataUpdate{{
+				DocumentID:   documentID,
+				MetadataList: []MetadataValue{{ID: fieldID, Name: "year", Value: 2024}},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	if resp := update(doc.Result.Document.Id, field.Result.ID); !resp.IsSuccess() || resp.Result.Result != "success" {
+		t.Fatalf("Expected metadata update to succeed, got %d %s", resp.StatusCode(), resp.Message)
+	}
+	docs := server.Documents(datasetID)
+	if len(docs) != 1 || !reflect.DeepEqual(docs[0].Metadata, map[string]interface{}{"year": float64(2024)}) {
+		t.Errorf("Expected document metadata to be set, got %+v", docs[0].Metadata)
+	}
+	list, err := c.ListMetadataFields(ctx, &ListMetadataFieldsRequest{DatasetID: datasetID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Result.DocMetadata[0].Count != 1 {
+		t.Errorf("Expected the field to be used by 1 document, got %d", list.Result.DocMetadata[0].Count)
+	}
+
+	if resp := update("missing", field.Result.ID); resp.StatusCode() != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing document, got %d", resp.StatusCode())
+	}
+	if resp := update(doc.Result.Document.Id, "missing"); resp.StatusCode() != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a missing field, got %d", resp.StatusCode())
+	}
+}
@@ -9,16 +9,13 @@ import (
 
 func TestRefreshToken(t *testing.T) {
 	// Create client
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
-	if err != nil {
-		t.Fatal(err)
-	}
+	c, _ := newTestClient(t)
 
 	// Cast to concrete type to access internal methods
 	client := c.(*client)
 
 	// Test refresh access token (for console API)
-	err = client.refreshAccessToken()
+	err := client.refreshAccessToken()
 	if err != nil {
 		t.Fatal("Failed to refresh console access token:", err)
 	}
@@ -36,10 +33,7 @@ func TestRefreshToken(t *testing.T) {
 
 func TestConsoleVsDatasetAPIs(t *testing.T) {
 	// Create client
-	c, err := NewClient("http://192.168.50.21:88", "likelovec@gmail.com", "Pwd123456")
-	if err != nil {
-		t.Fatal(err)
-	}
+	c, _ := newTestClient(t)
 
 	ctx := context.Background()
 
@@ -77,3 +71,28 @@ func TestConsoleVsDatasetAPIs(t *testing.T) {
 
 	t.Log("Console API (uses access token + refresh token) works correctly")
 }
+
+func TestRefreshOnExpiredAccessToken(t *testing.T) {
+	c, server := newTestClient(t)
+
+	// Expire every console access token, the next console call gets 401
+	server.ExpireAccessTokens()
+
+	resp, err := c.CreateChatApp(context.Background(), &CreateChatAppRequest{Name: "expired"})
+	if err != nil {
+		t.Fatal("Console API should refresh the access token:", err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal("Create chat app failed:", resp.Message)
+	}
+
+	refreshed := false
+	for _, r := range server.Requests() {
+		if r.Path == "/console/api/refresh-token" {
+			refreshed = true
+		}
+	}
+	if !refreshed {
+		t.Error("Expected the client to call /console/api/refresh-token")
+	}
+}
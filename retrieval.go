@@ -0,0 +1,293 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"resty.dev/v3"
+	"strconv"
+)
+
+// Retrieve 检索知识库
+// RetrievalModel 为空时使用知识库的默认检索配置
+func (c *client) Retrieve(ctx context.Context, req *RetrieveRequest) (*Response[RetrieveResponse], error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	var resp = &RetrieveResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post(fmt.Sprintf("/datasets/%s/retrieve", req.DatasetID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[RetrieveResponse](response, resp), nil
+}
+
+// HitTesting 召回测试
+// 与 Retrieve 返回相同的记录，但通过控制台接口执行，查询会记录到知识库的召回测试历史中
+func (c *client) HitTesting(ctx context.Context, req *RetrieveRequest) (*Response[RetrieveResponse], error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	var resultErr error
+	var resp = &RetrieveResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Post(fmt.Sprintf("/console/api/datasets/%s/hit-testing", req.DatasetID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to hit testing: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to hit testing with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[RetrieveResponse](finalResponse, resp), nil
+}
+
+// ListHitTestingQueries 分页获取知识库的召回测试历史
+func (c *client) ListHitTestingQueries(ctx context.Context, req *ListHitTestingQueriesRequest) (*Response[ListHitTestingQueriesResponse], error) {
+	var resultErr error
+	var resp = &ListHitTestingQueriesResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		request := c.console().
+			WithContext(ctx).
+			SetResult(&resp)
+		if req.Page > 0 {
+			request.SetQueryParam("page", strconv.Itoa(req.Page))
+		}
+		if req.Limit > 0 {
+			request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+		}
+		response, err := request.Get(fmt.Sprintf("/console/api/datasets/%s/queries", req.DatasetID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to list hit testing queries: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to list hit testing queries with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ListHitTestingQueriesResponse](finalResponse, resp), nil
+}
+
+// CompareRetrievalModels 使用两套检索配置对同一组查询执行召回测试，逐条返回对比结果
+func (c *client) CompareRetrievalModels(ctx context.Context, req *CompareRetrievalModelsRequest) ([]RetrievalComparison, error) {
+	comparisons := make([]RetrievalComparison, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		var runs [2]RetrievalRun
+		for i, model := range []RetrievalModel{req.A, req.B} {
+			model := model
+			resp, err := c.HitTesting(ctx, &RetrieveRequest{
+				DatasetID:      req.DatasetID,
+				Query:          query,
+				RetrievalModel: &model,
+			})
+			if err != nil {
+				return comparisons, err
+			}
+			runs[i] = newRetrievalRun(model, resp.Result.Records)
+		}
+		comparisons = append(comparisons, RetrievalComparison{
+			Query:          query,
+			A:              runs[0],
+			B:              runs[1],
+			CommonSegments: commonSegments(runs[0].Records, runs[1].Records),
+		})
+	}
+	return comparisons, nil
+}
+
+func newRetrievalRun(model RetrievalModel, records []RetrievalRecord) RetrievalRun {
+	run := RetrievalRun{
+		RetrievalModel: model,
+		SearchMethod:   model.SearchMethod,
+		Records:        records,
+	}
+	for _, record := range records {
+		if record.Score > run.MaxScore {
+			run.MaxScore = record.Score
+		}
+		run.MeanScore += record.Score
+	}
+	if len(records) > 0 {
+		run.MeanScore /= float64(len(records))
+	}
+	return run
+}
+
+// commonSegments 返回两次召回都命中的分段 ID，按 a 中的顺序排列
+func commonSegments(a, b []RetrievalRecord) []string {
+	hit := make(map[string]bool, len(b))
+	for _, record := range b {
+		hit[record.Segment.ID] = true
+	}
+	var common []string
+	for _, record := range a {
+		if hit[record.Segment.ID] {
+			common = append(common, record.Segment.ID)
+		}
+	}
+	return common
+}
+
+// RetrieveRequest 检索知识库请求
+type RetrieveRequest struct {
+	DatasetID      string          `json:"-"`                         // 知识库ID，不包含在JSON中
+	Query          string          `json:"query"`                     // 检索关键词
+	RetrievalModel *RetrievalModel `json:"retrieval_model,omitempty"` // 检索参数，为空时使用知识库默认配置
+}
+
+func (r *RetrieveRequest) validate() error {
+	if r.RetrievalModel != nil && r.RetrievalModel.MetadataFilteringConditions != nil {
+		return r.RetrievalModel.MetadataFilteringConditions.Validate()
+	}
+	return nil
+}
+
+// RetrieveResponse 检索知识库响应
+type RetrieveResponse struct {
+	Query struct {
+		Content string `json:"content"`
+	} `json:"query"`
+	Records []RetrievalRecord `json:"records"`
+}
+
+// RetrievalRecord 召回的一条记录
+type RetrievalRecord struct {
+	Segment      RetrievalSegment `json:"segment"`
+	Score        float64          `json:"score"`
+	TsnePosition interface{}      `json:"tsne_position"`
+	ChildChunks  []ChildChunk     `json:"child_chunks"` // parent-child 模式下命中的子分段
+}
+
+// RetrievalSegment 召回的分段
+type RetrievalSegment struct {
+	ID            string   `json:"id"`
+	Position      int      `json:"position"`
+	DocumentID    string   `json:"document_id"`
+	Content       string   `json:"content"`
+	Answer        string   `json:"answer"`
+	WordCount     int64    `json:"word_count"`
+	Tokens        int64    `json:"tokens"`
+	Keywords      []string `json:"keywords"`
+	IndexNodeID   string   `json:"index_node_id"`
+	IndexNodeHash string   `json:"index_node_hash"`
+	HitCount      int64    `json:"hit_count"`
+	Enabled       bool     `json:"enabled"`
+	Status        string   `json:"status"`
+	CreatedBy     string   `json:"created_by"`
+	CreatedAt     int64    `json:"created_at"`
+	IndexingAt    int64    `json:"indexing_at"`
+	CompletedAt   int64    `json:"completed_at"`
+	Document      struct {
+		ID             string `json:"id"`
+		DataSourceType string `json:"data_source_type"`
+		Name           string `json:"name"`
+	} `json:"document"`
+}
+
+// ChildChunk 子分段
+type ChildChunk struct {
+	ID       string  `json:"id"`
+	Content  string  `json:"content"`
+	Position int     `json:"position"`
+	Score    float64 `json:"score"`
+}
+
+// ListHitTestingQueriesRequest 获取召回测试历史请求
+type ListHitTestingQueriesRequest struct {
+	DatasetID string // 知识库ID
+	Page      int    // 页码，默认 1
+	Limit     int    // 每页数量，默认 20
+}
+
+// ListHitTestingQueriesResponse 召回测试历史响应
+type ListHitTestingQueriesResponse struct {
+	Data    []HitTestingQuery `json:"data"`
+	HasMore bool              `json:"has_more"`
+	Limit   int               `json:"limit"`
+	Total   int               `json:"total"`
+	Page    int               `json:"page"`
+}
+
+// HitTestingQuery 一条召回测试历史
+type HitTestingQuery struct {
+	ID            string `json:"id"`
+	Content       string `json:"content"`
+	Source        string `json:"source"` // hit_testing / app
+	SourceAppID   string `json:"source_app_id"`
+	CreatedByRole string `json:"created_by_role"`
+	CreatedBy     string `json:"created_by"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+// CompareRetrievalModelsRequest 对比检索配置请求
+type CompareRetrievalModelsRequest struct {
+	DatasetID string         // 知识库ID
+	Queries   []string       // 查询集合
+	A         RetrievalModel // 检索配置 A
+	B         RetrievalModel // 检索配置 B
+}
+
+// RetrievalComparison 单条查询在两套检索配置下的对比结果
+type RetrievalComparison struct {
+	Query          string
+	A              RetrievalRun
+	B              RetrievalRun
+	CommonSegments []string // 两套配置都召回的分段ID
+}
+
+// RetrievalRun 一次召回测试的结果
+type RetrievalRun struct {
+	RetrievalModel RetrievalModel             // 使用的检索配置
+	SearchMethod   RetrievalModelSearchMethod // 使用的检索方式
+	Records        []RetrievalRecord
+	MaxScore       float64
+	MeanScore      float64
+}
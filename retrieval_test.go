@@ -0,0 +1,161 @@
+package dify
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRetrievalRunStats(t *testing.T) {
+	record := func(id string, score float64) RetrievalRecord {
+		r := RetrievalRecord{Score: score}
+		r.Segment.ID = id
+		return r
+	}
+	a := newRetrievalRun(RetrievalModel{SearchMethod: RetrievalModelSearchMethodSemanticSearch, TopK: 3},
+		[]RetrievalRecord{record("s1", 0.9), record("s2", 0.6), record("s3", 0.3)})
+	b := newRetrievalRun(RetrievalModel{SearchMethod: RetrievalModelSearchMethodHybridSearch, TopK: 2},
+		[]RetrievalRecord{record("s3", 0.8), record("s1", 0.7)})
+
+	if a.MaxScore != 0.9 || a.MeanScore != 0.6 {
+		t.Errorf("Unexpected scores for A: max %v, mean %v", a.MaxScore, a.MeanScore)
+	}
+	if b.SearchMethod != RetrievalModelSearchMethodHybridSearch {
+		t.Errorf("Expected hybrid search for B, got '%s'", b.SearchMethod)
+	}
+	if got := commonSegments(a.Records, b.Records); !reflect.DeepEqual(got, []string{"s1", "s3"}) {
+		t.Errorf("Expected common segments [s1 s3], got %v", got)
+	}
+	if empty := newRetrievalRun(RetrievalModel{}, nil); empty.MeanScore != 0 {
+		t.Errorf("Expected zero mean score for empty run, got %v", empty.MeanScore)
+	}
+}
+
+// newRetrievalTestDataset 创建包含三个分段的知识库
+func newRetrievalTestDataset(t *testing.T, c Client) string {
+	t.Helper()
+	datasetID := newTestDataset(t, c)
+	resp, err := c.CreateByFile(context.Background(), &CreateByFileRequest{
+		DatasetsID:        datasetID,
+		Filename:          "notes.txt",
+		FileBody:          strings.NewReader("Go channels carry values\n\nGo interfaces describe behavior\n\nRust ownership rules"),
+		IndexingTechnique: IndexingTechniqueEconomy,
+		DocForm:           DocFormTextModel,
+		ProcessRule:       ProcessRule{Mode: ProcessModeAutomatic},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal(resp.Message)
+	}
+	return datasetID
+}
+
+func TestHitTesting(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetID := newRetrievalTestDataset(t, c)
+
+	resp, err := c.HitTesting(ctx, &RetrieveRequest{
+		DatasetID:      datasetID,
+		Query:          "go channels",
+		RetrievalModel: &RetrievalModel{SearchMethod: RetrievalModelSearchMethodSemanticSearch, TopK: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result.Query.Content != "go channels" || len(resp.Result.Records) != 1 {
+		t.Fatalf("Expected one record for the query, got %+v", resp.Result)
+	}
+	record := resp.Result.Records[0]
+	if record.Score != 1 || record.Segment.Content != "Go channels carry values" || record.Segment.Document.Name != "notes.txt" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+
+	// 不指定检索配置时使用默认配置；Retrieve 不记录召回测试历史
+	if resp, err = c.HitTesting(ctx, &RetrieveRequest{DatasetID: datasetID, Query: "go"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Result.Records) != 2 {
+		t.Errorf("Expected 2 records with the default model, got %d", len(resp.Result.Records))
+	}
+	if _, err := c.Retrieve(ctx, &RetrieveRequest{DatasetID: datasetID, Query: "rust"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 访问令牌过期时刷新后重试
+	server.ExpireAccessTokens()
+	if _, err := c.HitTesting(ctx, &RetrieveRequest{DatasetID: datasetID, Query: "rust"}); err != nil {
+		t.Fatal("Hit testing should refresh the access token:", err)
+	}
+	refreshed := false
+	for _, r := range server.Requests() {
+		if r.Path == "/console/api/refresh-token" {
+			refreshed = true
+		}
+	}
+	if !refreshed {
+		t.Error("Expected the client to call /console/api/refresh-token")
+	}
+
+	queries, err := c.ListHitTestingQueries(ctx, &ListHitTestingQueriesRequest{DatasetID: datasetID, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queries.Result.Total != 3 || !queries.Result.HasMore || len(queries.Result.Data) != 2 {
+		t.Fatalf("Expected 3 queries over two pages, got %+v", queries.Result)
+	}
+	if latest := queries.Result.Data[0]; latest.Content != "rust" || latest.Source != "hit_testing" {
+		t.Errorf("Expected the latest query first, got %+v", latest)
+	}
+
+	_, err = c.HitTesting(ctx, &RetrieveRequest{DatasetID: datasetID})
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("Expected 400 error for an empty query, got %v", err)
+	}
+	_, err = c.ListHitTestingQueries(ctx, &ListHitTestingQueriesRequest{DatasetID: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("Expected 404 error for a missing dataset, got %v", err)
+	}
+}
+
+func TestCompareRetrievalModels(t *testing.T) {
+	c, server := newTestClient(t)
+	datasetID := newRetrievalTestDataset(t, c)
+	comparisons, err := c.CompareRetrievalModels(context.Background(), &CompareRetrievalModelsRequest{
+		DatasetID: datasetID,
+		Queries:   []string{"go channels", "rust"},
+		A:         RetrievalModel{SearchMethod: RetrievalModelSearchMethodSemanticSearch, TopK: 1},
+		B:         RetrievalModel{SearchMethod: RetrievalModelSearchMethodHybridSearch, TopK: 3, ScoreThresholdEnabled: true, ScoreThreshold: 0.5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comparisons) != 2 {
+		t.Fatalf("Expected 2 comparisons, got %d", len(comparisons))
+	}
+	first := comparisons[0]
+	if len(first.A.Records) != 1 || len(first.B.Records) != 2 || len(first.CommonSegments) != 1 {
+		t.Errorf("Unexpected comparison %+v", first)
+	}
+	if first.A.SearchMethod != RetrievalModelSearchMethodSemanticSearch || first.B.MaxScore != 1 || first.B.MeanScore != 0.75 {
+		t.Errorf("Unexpected run stats A=%+v B=%+v", first.A, first.B)
+	}
+	hits := 0
+	for _, r := range server.Requests() {
+		if r.Method == http.MethodPost && r.Path == "/console/api/datasets/"+datasetID+"/hit-testing" {
+			hits++
+		}
+	}
+	if hits != 4 {
+		t.Errorf("Expected one hit testing call per query and model, got %d", hits)
+	}
+
+	server.FailNext(http.MethodPost, "/console/api/datasets/"+datasetID+"/hit-testing", http.StatusInternalServerError)
+	if _, err := c.CompareRetrievalModels(context.Background(), &CompareRetrievalModelsRequest{DatasetID: datasetID, Queries: []string{"go"}}); err == nil {
+		t.Error("Expected hit testing failure to be returned")
+	}
+}
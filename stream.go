@@ -0,0 +1,69 @@
+package dify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"resty.dev/v3"
+	"strings"
+)
+
+var sseLinePattern = regexp.MustCompile(`^(data|event|id|retry):\s?.*`)
+
+// streamEvents 读取 SSE 响应体，把每个 data 行解析为 T 并写入返回的 channel
+// 响应体读取完毕后关闭 channel 和响应体
+func streamEvents[T any](body io.ReadCloser) chan *T {
+	events := make(chan *T)
+	go func() {
+		defer close(events)
+		defer body.Close()
+
+		scanner := bufio.NewScanner(body)
+		// 单个事件可能携带较大的 outputs，放宽默认的 64KB 行长度限制
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if sseLinePattern.MatchString(line) {
+				event, err := parseSSELine[T](line)
+				if err != nil {
+					continue
+				}
+				events <- event
+			}
+		}
+	}()
+	return events
+}
+
+func parseSSELine[T any](line string) (*T, error) {
+	if strings.HasPrefix(line, "data: ") {
+		rawJSON := strings.TrimPrefix(line, "data: ")
+		var msg T
+		err := json.Unmarshal([]byte(rawJSON), &msg)
+		if err != nil {
+			return nil, err
+		}
+		return &msg, nil
+	}
+	return nil, fmt.Errorf("invalid line: does not start with 'data: '")
+}
+
+// readStreamError 读取并关闭未解析的错误响应体，返回包含状态码和响应内容的错误
+func readStreamError(response *resty.Response, action string) error {
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	return fmt.Errorf("failed to %s with status %d: %s", action, response.StatusCode(), string(body))
+}
+
+// readAppStreamError 与 readStreamError 相同，但应用 API 密钥被拒绝时返回 AppAuthError
+func readAppStreamError(response *resty.Response, action string) error {
+	if response.StatusCode() != http.StatusUnauthorized {
+		return readStreamError(response, action)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	return newAppAuthError(response.StatusCode(), body)
+}
@@ -0,0 +1,41 @@
+package dify
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamEvents(t *testing.T) {
+	body := io.NopCloser(strings.NewReader(strings.Join([]string{
+		`data: {"event": "message", "message_id": "m1", "answer": "Hello"}`,
+		``,
+		`event: ping`,
+		``,
+		`data: {"event": "message", "message_id": "m1", "answer": " world"}`,
+		``,
+		`data: {"event": "message_end", "message_id": "m1", "metadata": {"usage": {"total_tokens": 12, "total_price": "0.0001", "currency": "USD"}}}`,
+		``,
+	}, "\n")))
+
+	var answer string
+	var end *CompletionMessageChunk
+	for chunk := range streamEvents[CompletionMessageChunk](body) {
+		switch chunk.Event {
+		case MessageEventMessage:
+			answer += chunk.Answer
+		case MessageEventMessageEnd:
+			end = chunk
+		}
+	}
+
+	if answer != "Hello world" {
+		t.Errorf("Expected answer 'Hello world', got '%s'", answer)
+	}
+	if end == nil {
+		t.Fatal("Expected a message_end event")
+	}
+	if end.Metadata.Usage.TotalTokens != 12 {
+		t.Errorf("Expected 12 total tokens, got %d", end.Metadata.Usage.TotalTokens)
+	}
+}
@@ -0,0 +1,359 @@
+package dify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"time"
+)
+
+// SyncDocuments 根据清单把目录同步到知识库，重复执行结果收敛
+// 清单中没有的文件先按名称匹配知识库中已有的文档并更新，匹配不到时通过 CreateByFile 创建；
+// 内容变化的文件通过 UpdateByFile 更新，内容不变的文件不会上传，来源已不存在的文档按 Removal 删除或归档。
+// 报告中的 Manifest 为同步后的清单，调用方负责保存
+func (c *client) SyncDocuments(ctx context.Context, req *SyncRequest) (*SyncReport, error) {
+	if req.DatasetID == "" {
+		return nil, fmt.Errorf("sync requires a dataset ID")
+	}
+	if err := req.Document.ProcessRule.Validate(req.Document.DocForm); err != nil {
+		return nil, err
+	}
+	fsys := req.FS
+	if fsys == nil {
+		if req.Dir == "" {
+			return nil, fmt.Errorf("sync requires a directory or fs.FS")
+		}
+		fsys = os.DirFS(req.Dir)
+	}
+	manifest := req.Manifest
+	if manifest == nil {
+		manifest = &SyncManifest{}
+	}
+	if manifest.DatasetID != "" && manifest.DatasetID != req.DatasetID {
+		return nil, fmt.Errorf("manifest belongs to dataset %s, not %s", manifest.DatasetID, req.DatasetID)
+	}
+	paths, err := req.matchFiles(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	next := &SyncManifest{DatasetID: req.DatasetID, Files: map[string]SyncManifestEntry{}}
+	report := &SyncReport{Manifest: next}
+	type upload struct {
+		index int
+		hash  string
+		entry SyncManifestEntry
+	}
+	var uploads []upload
+	// 知识库中已有的文档，需要按名称匹配或创建文档时才获取
+	var docs []Document
+	var listed bool
+	var existing map[string]string
+	listDocuments := func() error {
+		if listed {
+			return nil
+		}
+		list, err := c.listAllDocuments(ctx, req.DatasetID, "")
+		if err != nil {
+			return err
+		}
+		docs, listed = list, true
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, p := range paths {
+		seen[p] = true
+		entry, ok := manifest.Files[p]
+		if !req.supported(p) {
+			if ok {
+				next.Files[p] = entry
+			}
+			report.Files = append(report.Files, SyncFileResult{
+				Action:           SyncActionSkip,
+				IngestFileResult: IngestFileResult{Path: p, Status: IngestStatusSkipped, Error: "unsupported file extension"},
+			})
+			continue
+		}
+		hash, err := hashFile(fsys, p)
+		if err != nil {
+			if ok {
+				next.Files[p] = entry
+			}
+			report.Files = append(report.Files, SyncFileResult{
+				Action:           SyncActionSkip,
+				IngestFileResult: IngestFileResult{Path: p, Status: IngestStatusFailed, Error: err.Error(), Err: err},
+			})
+			continue
+		}
+		if ok && entry.DocumentID != "" && entry.Hash == hash {
+			next.Files[p] = entry
+			report.Files = append(report.Files, SyncFileResult{
+				Action:           SyncActionUnchanged,
+				IngestFileResult: IngestFileResult{Path: p, DocumentID: entry.DocumentID, Batch: entry.Batch, Status: IngestStatusSkipped},
+			})
+			continue
+		}
+		if entry.DocumentID == "" {
+			// 清单丢失或同步中断时，知识库中可能已有该文件的文档，先按名称匹配，避免重复创建
+			if existing == nil {
+				if err := listDocuments(); err != nil {
+					return nil, err
+				}
+				existing = matchSyncDocuments(docs, manifest, paths)
+			}
+			entry = SyncManifestEntry{DocumentID: existing[path.Base(p)]}
+		}
+		action := SyncActionCreate
+		if entry.DocumentID != "" {
+			action = SyncActionUpdate
+		}
+		uploads = append(uploads, upload{index: len(report.Files), hash: hash, entry: entry})
+		report.Files = append(report.Files, SyncFileResult{Action: action, IngestFileResult: IngestFileResult{Path: p}})
+	}
+
+	var recovery *ingestRecovery
+	if len(uploads) > 0 {
+		if err := listDocuments(); err != nil {
+			return nil, err
+		}
+		uploadPaths := make([]string, len(uploads))
+		for j, u := range uploads {
+			uploadPaths[j] = report.Files[u.index].Path
+		}
+		recovery = newIngestRecovery(docs, uploadPaths)
+	}
+	req.run(len(uploads), func(j int, limiter <-chan time.Time) {
+		u := uploads[j]
+		result := &report.Files[u.index]
+		c.ingestFile(ctx, &req.IngestRequest, fsys, limiter, &result.IngestFileResult, u.entry.DocumentID, recovery)
+		if u.entry.DocumentID != "" && result.DocumentID != "" && result.DocumentID != u.entry.DocumentID {
+			// 原文档已在服务端被删除，改为重新创建
+			result.Action = SyncActionCreate
+		}
+	})
+	for _, u := range uploads {
+		result := report.Files[u.index]
+		switch {
+		case result.Succeeded():
+			next.Files[result.Path] = SyncManifestEntry{DocumentID: result.DocumentID, Hash: u.hash, Batch: result.Batch}
+		case result.DocumentID != "":
+			// 已上传但嵌入失败，清空哈希使下次同步重新更新该文档
+			next.Files[result.Path] = SyncManifestEntry{DocumentID: result.DocumentID, Batch: result.Batch}
+		case u.entry.DocumentID != "":
+			next.Files[result.Path] = u.entry
+		}
+	}
+
+	var removed []string
+	for p, entry := range manifest.Files {
+		if !seen[p] && entry.DocumentID != "" {
+			removed = append(removed, p)
+		}
+	}
+	sort.Strings(removed)
+	results := c.removeSyncDocuments(ctx, req, manifest, removed)
+	for _, result := range results {
+		if result.Status == IngestStatusFailed {
+			next.Files[result.Path] = manifest.Files[result.Path]
+		}
+	}
+	report.Files = append(report.Files, results...)
+	return report, nil
+}
+
+// matchSyncDocuments 返回知识库文档 docs 中可与 paths 按名称对应的文档：文档名 -> 文档ID
+// 已记录在清单中的文档、已归档的文档以及多个文件同名的情况不参与匹配，同名文档有多个时取最新创建的
+func matchSyncDocuments(docs []Document, manifest *SyncManifest, paths []string) map[string]string {
+	names := map[string]int{}
+	for _, p := range paths {
+		names[path.Base(p)]++
+	}
+	tracked := map[string]bool{}
+	for _, entry := range manifest.Files {
+		tracked[entry.DocumentID] = true
+	}
+
+	existing := map[string]string{}
+	createdAt := map[string]int64{}
+	for _, doc := range docs {
+		if names[doc.Name] != 1 || doc.Archived || tracked[doc.Id] {
+			continue
+		}
+		if _, ok := existing[doc.Name]; !ok || doc.CreatedAt > createdAt[doc.Name] {
+			existing[doc.Name] = doc.Id
+			createdAt[doc.Name] = doc.CreatedAt
+		}
+	}
+	return existing
+}
+
+// removeSyncDocuments 删除或归档来源已不存在的文档，删除时文档已不存在视为成功
+func (c *client) removeSyncDocuments(ctx context.Context, req *SyncRequest, manifest *SyncManifest, paths []string) []SyncFileResult {
+	results := make([]SyncFileResult, len(paths))
+	for i, p := range paths {
+		results[i] = SyncFileResult{
+			Action:           SyncActionDelete,
+			IngestFileResult: IngestFileResult{Path: p, DocumentID: manifest.Files[p].DocumentID, Status: IngestStatusCompleted},
+		}
+	}
+	fail := func(result *SyncFileResult, err error) {
+		result.Status = IngestStatusFailed
+		result.Error = err.Error()
+		result.Err = err
+	}
+
+	if req.Removal == SyncRemovalArchive {
+		if len(paths) == 0 {
+			return results
+		}
+		ids := make([]string, len(paths))
+		for i := range results {
+			results[i].Action = SyncActionArchive
+			ids[i] = results[i].DocumentID
+		}
+		resp, err := c.UpdateDocumentsStatus(ctx, &UpdateDocumentsStatusRequest{
+			DatasetID:   req.DatasetID,
+			Action:      DocumentStatusActionArchive,
+			DocumentIDs: ids,
+		})
+		if err == nil && resp.IsError() {
+			err = fmt.Errorf("failed to archive documents with status %d: %s", resp.StatusCode(), resp.Message)
+		}
+		if err != nil {
+			for i := range results {
+				fail(&results[i], err)
+			}
+		}
+		return results
+	}
+
+	for i := range results {
+		resp, err := c.DeleteDocument(ctx, &DeleteDocumentRequest{DatasetID: req.DatasetID, DocumentID: results[i].DocumentID})
+		if err == nil && resp.IsError() && resp.StatusCode() != 404 {
+			err = fmt.Errorf("failed to delete document with status %d: %s", resp.StatusCode(), resp.Message)
+		}
+		if err != nil {
+			fail(&results[i], err)
+		}
+	}
+	return results
+}
+
+// hashFile 计算文件内容的 SHA-256
+func hashFile(fsys fs.FS, p string) (string, error) {
+	file, err := fsys.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SyncRequest 同步目录到知识库请求
+// 文件来源、并发、限速、重试和文档设置与 IngestFiles 相同，Previous 不生效
+type SyncRequest struct {
+	IngestRequest
+	Manifest *SyncManifest // 上次同步后的清单，为空时视为首次同步
+	Removal  SyncRemoval   // 来源已不存在的文档的处理方式，默认删除
+}
+
+// SyncRemoval 来源已不存在的文档的处理方式
+type SyncRemoval string
+
+const (
+	SyncRemovalDelete  SyncRemoval = "delete"  // 删除文档
+	SyncRemovalArchive SyncRemoval = "archive" // 归档文档，之后来源恢复时会创建新文档
+)
+
+// SyncAction 同步时对单个文件执行的操作
+type SyncAction string
+
+const (
+	SyncActionCreate    SyncAction = "create"    // 新文件，创建文档
+	SyncActionUpdate    SyncAction = "update"    // 内容变化，更新文档
+	SyncActionUnchanged SyncAction = "unchanged" // 内容不变，不上传
+	SyncActionDelete    SyncAction = "delete"    // 来源已不存在，删除文档
+	SyncActionArchive   SyncAction = "archive"   // 来源已不存在，归档文档
+	SyncActionSkip      SyncAction = "skip"      // 扩展名不受支持或读取失败
+)
+
+// SyncReport 同步报告
+type SyncReport struct {
+	Files    []SyncFileResult `json:"files"`
+	Manifest *SyncManifest    `json:"-"` // 同步后的清单，失败的文件保留上次的记录，下次同步会重试
+}
+
+// Failed 返回失败的文件
+func (r *SyncReport) Failed() []SyncFileResult {
+	var failed []SyncFileResult
+	for _, result := range r.Files {
+		if result.Status == IngestStatusFailed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// SyncFileResult 单个文件的同步结果
+// 内容不变的文件 Status 为 skipped，删除或归档成功时 Status 为 completed
+type SyncFileResult struct {
+	Action SyncAction `json:"action"`
+	IngestFileResult
+}
+
+// SyncManifest 同步清单，记录来源文件与文档的对应关系
+type SyncManifest struct {
+	DatasetID string                       `json:"dataset_id"`
+	Files     map[string]SyncManifestEntry `json:"files"` // 相对路径 -> 文档
+}
+
+// SyncManifestEntry 清单中的一个文件
+type SyncManifestEntry struct {
+	DocumentID string `json:"document_id"`
+	Hash       string `json:"hash"` // 内容的 SHA-256，嵌入失败时为空，下次同步会重新上传
+	Batch      string `json:"batch,omitempty"`
+}
+
+// LoadSyncManifest 从本地文件读取清单，文件不存在时返回空清单
+func LoadSyncManifest(filename string) (*SyncManifest, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &SyncManifest{Files: map[string]SyncManifestEntry{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest SyncManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse sync manifest %s: %w", filename, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]SyncManifestEntry{}
+	}
+	return &manifest, nil
+}
+
+// Save 把清单写入本地文件，先写临时文件再重命名，避免中断时损坏清单
+func (m *SyncManifest) Save(filename string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
@@ -0,0 +1,194 @@
+package dify
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func syncActions(report *SyncReport) map[string]SyncAction {
+	actions := map[string]SyncAction{}
+	for _, result := range report.Files {
+		actions[result.Path] = result.Action
+	}
+	return actions
+}
+
+func TestSyncDocuments(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetResp, err := c.CreateEmptyDataset(ctx, &CreateEmptyDatasetRequest{Name: "sync"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datasetID := datasetResp.Result.ID
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("alpha")},
+		"b.md":  {Data: []byte("# beta")},
+	}
+	req := &SyncRequest{IngestRequest: IngestRequest{
+		DatasetID: datasetID,
+		FS:        fsys,
+		Document:  CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+	}}
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+
+	report, err := c.SyncDocuments(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Manifest.Save(manifestPath); err != nil {
+		t.Fatal(err)
+	}
+	if actions := syncActions(report); actions["a.txt"] != SyncActionCreate || actions["b.md"] != SyncActionCreate {
+		t.Fatalf("Expected both files to be created, got %v", actions)
+	}
+	betaID := report.Manifest.Files["b.md"].DocumentID
+
+	delete(fsys, "a.txt")
+	fsys["b.md"] = &fstest.MapFile{Data: []byte("# beta v2")}
+	fsys["c.txt"] = &fstest.MapFile{Data: []byte("gamma")}
+	manifest, err := LoadSyncManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Manifest = manifest
+	report, err = c.SyncDocuments(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed := report.Failed(); len(failed) != 0 {
+		t.Fatalf("Unexpected failures: %+v", failed)
+	}
+	want := map[string]SyncAction{"a.txt": SyncActionDelete, "b.md": SyncActionUpdate, "c.txt": SyncActionCreate}
+	for p, action := range syncActions(report) {
+		if want[p] != action {
+			t.Errorf("%s: expected %s, got %s", p, want[p], action)
+		}
+	}
+	docs := server.Documents(datasetID)
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 documents, got %d", len(docs))
+	}
+	for _, doc := range docs {
+		if doc.Name == "b.md" && (doc.ID != betaID || string(doc.Content) != "# beta v2") {
+			t.Errorf("Expected b.md to be updated in place, got %+v", doc)
+		}
+	}
+	if _, ok := report.Manifest.Files["a.txt"]; ok {
+		t.Error("Expected removed file to be dropped from manifest")
+	}
+
+	// 再次同步不应产生任何上传
+	before := len(server.Requests())
+	req.Manifest = report.Manifest
+	report, err = c.SyncDocuments(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, action := range syncActions(report) {
+		if action != SyncActionUnchanged {
+			t.Errorf("%s: expected unchanged, got %s", p, action)
+		}
+	}
+	if n := len(server.Requests()) - before; n != 0 {
+		t.Errorf("Expected no requests, got %d", n)
+	}
+}
+
+func TestSyncDocumentsArchiveAndRecreate(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetResp, err := c.CreateEmptyDataset(ctx, &CreateEmptyDatasetRequest{Name: "sync"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datasetID := datasetResp.Result.ID
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("alpha")},
+		"b.txt": {Data: []byte("beta")},
+	}
+	req := &SyncRequest{
+		IngestRequest: IngestRequest{DatasetID: datasetID, FS: fsys},
+		Removal:       SyncRemovalArchive,
+	}
+	report, err := c.SyncDocuments(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// b.txt 对应的文档在服务端被手动删除后，更新应改为重新创建
+	betaID := report.Manifest.Files["b.txt"].DocumentID
+	if _, err := c.DeleteDocument(ctx, &DeleteDocumentRequest{DatasetID: datasetID, DocumentID: betaID}); err != nil {
+		t.Fatal(err)
+	}
+	delete(fsys, "a.txt")
+	fsys["b.txt"] = &fstest.MapFile{Data: []byte("beta v2")}
+	req.Manifest = report.Manifest
+	report, err = c.SyncDocuments(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := syncActions(report)
+	if actions["a.txt"] != SyncActionArchive || actions["b.txt"] != SyncActionCreate {
+		t.Fatalf("Unexpected actions %v", actions)
+	}
+	docs := server.Documents(datasetID)
+	if len(docs) != 2 || !docs[0].Archived || docs[1].ID == betaID {
+		t.Errorf("Expected archived a.txt and recreated b.txt, got %+v", docs)
+	}
+	if id := report.Manifest.Files["b.txt"].DocumentID; id != docs[1].ID {
+		t.Errorf("Expected manifest to point at recreated document, got %s", id)
+	}
+}
+
+func TestSyncDocumentsWithoutManifest(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	datasetID := newTestDataset(t, c)
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("alpha")},
+		"b.md":  {Data: []byte("# beta")},
+	}
+	req := &SyncRequest{IngestRequest: IngestRequest{
+		DatasetID: datasetID,
+		FS:        fsys,
+		Document:  CreateByFileRequest{IndexingTechnique: IndexingTechniqueEconomy, DocForm: DocFormTextModel},
+	}}
+	first, err := c.SyncDocuments(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 清单丢失后再次同步：已有文档按名称匹配并更新，只有新文件才创建
+	fsys["b.md"] = &fstest.MapFile{Data: []byte("# beta v2")}
+	fsys["c.txt"] = &fstest.MapFile{Data: []byte("gamma")}
+	report, err := c.SyncDocuments(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed := report.Failed(); len(failed) != 0 {
+		t.Fatalf("Unexpected failures: %+v", failed)
+	}
+	want := map[string]SyncAction{"a.txt": SyncActionUpdate, "b.md": SyncActionUpdate, "c.txt": SyncActionCreate}
+	for p, action := range syncActions(report) {
+		if want[p] != action {
+			t.Errorf("%s: expected %s, got %s", p, want[p], action)
+		}
+	}
+	for _, p := range []string{"a.txt", "b.md"} {
+		if id := report.Manifest.Files[p].DocumentID; id != first.Manifest.Files[p].DocumentID {
+			t.Errorf("%s: expected existing document to be reused, got %s", p, id)
+		}
+	}
+	docs := server.Documents(datasetID)
+	if len(docs) != 3 {
+		t.Fatalf("Expected 3 documents without duplicates, got %d", len(docs))
+	}
+	for _, doc := range docs {
+		if doc.Name == "b.md" && string(doc.Content) != "# beta v2" {
+			t.Errorf("Expected b.md to be updated, got %q", doc.Content)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package dify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ListKnowledgeTags 获取知识库类型的标签列表
+func (c *client) ListKnowledgeTags(ctx context.Context) (*Response[[]Tag], error) {
+	var resp = &[]Tag{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get("/datasets/tags")
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[[]Tag](response, resp), nil
+}
+
+// CreateKnowledgeTag 新增知识库类型的标签
+func (c *client) CreateKnowledgeTag(ctx context.Context, req *CreateKnowledgeTagRequest) (*Response[Tag], error) {
+	var resp = &Tag{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post("/datasets/tags")
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[Tag](response, resp), nil
+}
+
+// UpdateKnowledgeTag 修改知识库类型标签的名称
+func (c *client) UpdateKnowledgeTag(ctx context.Context, req *UpdateKnowledgeTagRequest) (*Response[Tag], error) {
+	var resp = &Tag{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Patch("/datasets/tags")
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[Tag](response, resp), nil
+}
+
+// DeleteKnowledgeTag 删除知识库类型的标签
+func (c *client) DeleteKnowledgeTag(ctx context.Context, req *DeleteKnowledgeTagRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetAllowMethodDeletePayload(true).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Delete("/datasets/tags")
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// BindDatasetTags 为知识库绑定标签
+func (c *client) BindDatasetTags(ctx context.Context, req *BindDatasetTagsRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post("/datasets/tags/binding")
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// UnbindDatasetTag 解除知识库的标签
+func (c *client) UnbindDatasetTag(ctx context.Context, req *UnbindDatasetTagRequest) (*Response[ResultResponse], error) {
+	var resp = &ResultResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetContentType("application/json").
+		SetBody(req).
+		SetResult(&resp).
+		Post("/datasets/tags/unbinding")
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ResultResponse](response, resp), nil
+}
+
+// GetDatasetTags 获取知识库已绑定的标签
+func (c *client) GetDatasetTags(ctx context.Context, req *GetDatasetTagsRequest) (*Response[GetDatasetTagsResponse], error) {
+	var resp = &GetDatasetTagsResponse{}
+	response, err := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get(fmt.Sprintf("/datasets/%s/tags", req.DatasetID))
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[GetDatasetTagsResponse](response, resp), nil
+}
+
+// ListDatasets 分页获取知识库列表，可按关键字和标签过滤
+func (c *client) ListDatasets(ctx context.Context, req *ListDatasetsRequest) (*Response[ListDatasetsResponse], error) {
+	var resp = &ListDatasetsResponse{}
+	request := c.datasets().
+		WithContext(ctx).
+		SetResult(&resp)
+	if req.Page > 0 {
+		request.SetQueryParam("page", strconv.Itoa(req.Page))
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Keyword != "" {
+		request.SetQueryParam("keyword", req.Keyword)
+	}
+	if len(req.TagIDs) > 0 {
+		request.SetQueryParamsFromValues(map[string][]string{"tag_ids": req.TagIDs})
+	}
+	if req.IncludeAll {
+		request.SetQueryParam("include_all", "true")
+	}
+	response, err := request.Get("/datasets")
+	if err != nil {
+		return nil, err
+	}
+	return buildResponse[ListDatasetsResponse](response, resp), nil
+}
+
+// Tag 标签
+type Tag struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"` // knowledge / app
+	BindingCount int    `json:"binding_count"`
+}
+
+// CreateKnowledgeTagRequest 新增标签请求
+type CreateKnowledgeTagRequest struct {
+	Name string `json:"name"` // 标签名称，最长 50 个字符
+}
+
+// UpdateKnowledgeTagRequest 修改标签请求
+type UpdateKnowledgeTagRequest struct {
+	TagID string `json:"tag_id"`
+	Name  string `json:"name"`
+}
+
+// DeleteKnowledgeTagRequest 删除标签请求
+type DeleteKnowledgeTagRequest struct {
+	TagID string `json:"tag_id"`
+}
+
+// BindDatasetTagsRequest 绑定标签请求
+type BindDatasetTagsRequest struct {
+	TagIDs   []string `json:"tag_ids"`
+	TargetID string   `json:"target_id"` // 知识库ID
+}
+
+// UnbindDatasetTagRequest 解绑标签请求
+type UnbindDatasetTagRequest struct {
+	TagID    string `json:"tag_id"`
+	TargetID string `json:"target_id"` // 知识库ID
+}
+
+// GetDatasetTagsRequest 获取知识库标签请求
+type GetDatasetTagsRequest struct {
+	DatasetID string // 知识库ID
+}
+
+// GetDatasetTagsResponse 知识库标签响应
+type GetDatasetTagsResponse struct {
+	Data  []Tag `json:"data"`
+	Total int   `json:"total"`
+}
+
+// ListDatasetsRequest 获取知识库列表请求
+type ListDatasetsRequest struct {
+	Page       int      // 页码，默认 1
+	Limit      int      // 每页数量，默认 20
+	Keyword    string   // 搜索关键字
+	TagIDs     []string // 标签ID，返回同时绑定了这些标签的知识库
+	IncludeAll bool     // 是否包含所有知识库（仅对所有者生效）
+}
+
+// ListDatasetsResponse 知识库列表响应
+type ListDatasetsResponse struct {
+	Data    []Dataset `json:"data"`
+	HasMore bool      `json:"has_more"`
+	Limit   int       `json:"limit"`
+	Total   int       `json:"total"`
+	Page    int       `json:"page"`
+}
+
+// Dataset 知识库
+type Dataset struct {
+	CreateEmptyDatasetResponse
+	DocForm        DocForm        `json:"doc_form"`
+	RetrievalModel RetrievalModel `json:"retrieval_model_dict"`
+	Tags           []Tag          `json:"tags"`
+}
@@ -0,0 +1,142 @@
+package dify
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestKnowledgeTags(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := c.CreateKnowledgeTag(ctx, &CreateKnowledgeTagRequest{Name: "财务"})
+	if err != nil {
+		t.Fatal("Failed to create tag:", err)
+	}
+	if !created.IsSuccess() || created.Result.ID == "" || created.Result.Type != "knowledge" {
+		t.Fatalf("Unexpected create tag response: %d %+v", created.StatusCode(), created.Result)
+	}
+	tagID := created.Result.ID
+
+	updated, err := c.UpdateKnowledgeTag(ctx, &UpdateKnowledgeTagRequest{TagID: tagID, Name: "财务制度"})
+	if err != nil {
+		t.Fatal("Failed to update tag:", err)
+	}
+	if updated.Result.Name != "财务制度" {
+		t.Errorf("Expected renamed tag, got '%s'", updated.Result.Name)
+	}
+
+	list, err := c.ListKnowledgeTags(ctx)
+	if err != nil {
+		t.Fatal("Failed to list tags:", err)
+	}
+	if len(*list.Result) != 1 || (*list.Result)[0].Name != "财务制度" {
+		t.Errorf("Unexpected tags: %+v", list.Result)
+	}
+
+	deleted, err := c.DeleteKnowledgeTag(ctx, &DeleteKnowledgeTagRequest{TagID: tagID})
+	if err != nil {
+		t.Fatal("Failed to delete tag:", err)
+	}
+	if !deleted.IsSuccess() {
+		t.Fatal("Delete tag failed:", deleted.Message)
+	}
+	list, err = c.ListKnowledgeTags(ctx)
+	if err != nil {
+		t.Fatal("Failed to list tags:", err)
+	}
+	if len(*list.Result) != 0 {
+		t.Errorf("Expected no tags after delete, got %+v", list.Result)
+	}
+
+	missing, err := c.UpdateKnowledgeTag(ctx, &UpdateKnowledgeTagRequest{TagID: tagID, Name: "x"})
+	if err != nil {
+		t.Fatal("Failed to update tag:", err)
+	}
+	if missing.StatusCode() != 404 {
+		t.Errorf("Expected 404 for deleted tag, got %d", missing.StatusCode())
+	}
+}
+
+func TestBindDatasetTags(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	var tagIDs []string
+	for _, name := range []string{"财务", "人事"} {
+		resp, err := c.CreateKnowledgeTag(ctx, &CreateKnowledgeTagRequest{Name: name})
+		if err != nil {
+			t.Fatal("Failed to create tag:", err)
+		}
+		tagIDs = append(tagIDs, resp.Result.ID)
+	}
+
+	// 创建时绑定
+	dataset, err := c.CreateEmptyDataset(ctx, &CreateEmptyDatasetRequest{Name: "制度", TagIDs: tagIDs})
+	if err != nil {
+		t.Fatal("Failed to create dataset:", err)
+	}
+	datasetID := dataset.Result.ID
+	tags, err := c.GetDatasetTags(ctx, &GetDatasetTagsRequest{DatasetID: datasetID})
+	if err != nil {
+		t.Fatal("Failed to get dataset tags:", err)
+	}
+	if tags.Result.Total != 2 {
+		t.Fatalf("Expected 2 bound tags, got %+v", tags.Result)
+	}
+
+	unbind, err := c.UnbindDatasetTag(ctx, &UnbindDatasetTagRequest{TagID: tagIDs[0], TargetID: datasetID})
+	if err != nil {
+		t.Fatal("Failed to unbind tag:", err)
+	}
+	if !unbind.IsSuccess() {
+		t.Fatal("Unbind tag failed:", unbind.Message)
+	}
+	tags, err = c.GetDatasetTags(ctx, &GetDatasetTagsRequest{DatasetID: datasetID})
+	if err != nil {
+		t.Fatal("Failed to get dataset tags:", err)
+	}
+	if tags.Result.Total != 1 || tags.Result.Data[0].ID != tagIDs[1] || tags.Result.Data[0].BindingCount != 1 {
+		t.Errorf("Expected only the second tag to remain bound, got %+v", tags.Result)
+	}
+
+	bind, err := c.BindDatasetTags(ctx, &BindDatasetTagsRequest{TagIDs: tagIDs[:1], TargetID: datasetID})
+	if err != nil {
+		t.Fatal("Failed to bind tags:", err)
+	}
+	if !bind.IsSuccess() {
+		t.Fatal("Bind tags failed:", bind.Message)
+	}
+	list, err := c.ListDatasets(ctx, &ListDatasetsRequest{TagIDs: tagIDs})
+	if err != nil {
+		t.Fatal("Failed to list datasets:", err)
+	}
+	if len(list.Result.Data) != 1 || len(list.Result.Data[0].Tags) != 2 {
+		t.Errorf("Expected dataset filtered by both tags, got %+v", list.Result.Data)
+	}
+}
+
+func TestCreateEmptyDatasetBindFailure(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	resp, err := c.CreateEmptyDataset(ctx, &CreateEmptyDatasetRequest{Name: "制度", TagIDs: []string{"missing"}})
+	if err == nil || !strings.Contains(err.Error(), "failed to bind tags") {
+		t.Fatalf("Expected bind error, got %v", err)
+	}
+	if resp == nil || !resp.IsSuccess() || resp.Result.ID == "" {
+		t.Fatal("Expected the created dataset to be returned with the bind error")
+	}
+	if !strings.Contains(err.Error(), resp.Result.ID) {
+		t.Errorf("Expected error to name dataset %s, got %v", resp.Result.ID, err)
+	}
+
+	list, err := c.ListDatasets(ctx, &ListDatasetsRequest{})
+	if err != nil {
+		t.Fatal("Failed to list datasets:", err)
+	}
+	if len(list.Result.Data) != 1 || list.Result.Data[0].ID != resp.Result.ID {
+		t.Errorf("Expected the dataset to be kept, got %+v", list.Result.Data)
+	}
+}
@@ -0,0 +1,280 @@
+package dify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// ErrFileTooLarge 文件超过上传大小限制
+var ErrFileTooLarge = errors.New("file exceeds upload size limit")
+
+// GetUploadConfig 获取服务端的文件上传限制
+func (c *client) GetUploadConfig(ctx context.Context) (*Response[UploadConfig], error) {
+	var resultErr error
+	var resp = &UploadConfig{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetResult(&resp).
+			Get("/console/api/files/upload")
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to get upload config: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to get upload config with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[UploadConfig](finalResponse, resp), nil
+}
+
+// uploadLimitRetryInterval 获取上传限制失败后，间隔多久再次尝试
+const uploadLimitRetryInterval = time.Minute
+
+// documentUploadLimit 返回服务端允许的文档大小（字节），首次获取成功后缓存
+// 获取失败、正在获取或失败后尚未到重试时间时返回 0，此时只检查请求中的 MaxFileSize，由服务端拒绝超限的文件。
+// 获取时不持有锁，并使用独立的 ctx，不受单次上传取消的影响，也不会阻塞并发的上传
+func (c *client) documentUploadLimit() int64 {
+	c.uploadLimitMu.Lock()
+	if c.uploadLimit > 0 || c.uploadLimitFetching || time.Since(c.uploadLimitFailedAt) < uploadLimitRetryInterval {
+		limit := c.uploadLimit
+		c.uploadLimitMu.Unlock()
+		return limit
+	}
+	c.uploadLimitFetching = true
+	c.uploadLimitMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := c.GetUploadConfig(ctx)
+
+	c.uploadLimitMu.Lock()
+	defer c.uploadLimitMu.Unlock()
+	c.uploadLimitFetching = false
+	if err != nil || !resp.IsSuccess() || resp.Result.FileSizeLimit <= 0 {
+		c.uploadLimitFailedAt = time.Now()
+		return 0
+	}
+	c.uploadLimit = int64(resp.Result.FileSizeLimit) << 20
+	return c.uploadLimit
+}
+
+// postDocumentFile 以流式 multipart 上传文档文件，文件内容不会整体读入内存
+func (c *client) postDocumentFile(ctx context.Context, url string, data []byte, file *documentFile, result interface{}) (*resty.Response, error) {
+	return c.postMultipartFile(c.datasets().WithContext(ctx), url, data, file, result)
+}
+
+// postMultipartFile 使用 request 以流式 multipart 上传文件，data 为 nil 时只发送 file 字段
+// 写入失败（如超过大小限制）时中断请求，服务端不会收到不完整的文件
+func (c *client) postMultipartFile(request *resty.Request, url string, data []byte, file *documentFile, result interface{}) (*resty.Response, error) {
+	size := file.Size
+	if size <= 0 {
+		size = readerSize(file.Body)
+	}
+	limit := file.MaxSize
+	if limit <= 0 {
+		limit = c.documentUploadLimit()
+	}
+	if limit > 0 && size > limit {
+		return nil, fmt.Errorf("%s is %d bytes, limit is %d bytes: %w", file.Filename, size, limit, ErrFileTooLarge)
+	}
+
+	body := bufio.NewReaderSize(file.Body, 512)
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = detectContentType(file.Filename, body)
+	}
+	var reader io.Reader = body
+	if limit > 0 || file.Progress != nil {
+		reader = &progressReader{
+			r:        body,
+			limit:    limit,
+			progress: file.Progress,
+			state:    UploadProgress{Filename: file.Filename, Total: size},
+		}
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	writeErr := make(chan error, 1)
+	go func() {
+		err := writeDocumentMultipart(mw, data, file.Filename, contentType, reader)
+		pw.CloseWithError(err)
+		writeErr <- err
+	}()
+
+	response, err := request.
+		SetHeader("Content-Type", mw.FormDataContentType()).
+		SetBody(pr).
+		SetResult(result).
+		Post(url)
+	// 服务端提前返回时 writer 可能仍阻塞在管道上，关闭读端使其退出
+	pr.Close()
+	if werr := <-writeErr; werr != nil && !errors.Is(werr, io.ErrClosedPipe) {
+		return nil, werr
+	}
+	return response, err
+}
+
+func writeDocumentMultipart(mw *multipart.Writer, data []byte, filename, contentType string, r io.Reader) error {
+	if data != nil {
+		if err := mw.WriteField("data", string(data)); err != nil {
+			return err
+		}
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filename)))
+	h.Set("Content-Type", contentType)
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// documentContentTypes 常见文档扩展名对应的 MIME 类型，不依赖系统的 mime.types
+var documentContentTypes = map[string]string{
+	".txt":        "text/plain",
+	".md":         "text/markdown",
+	".markdown":   "text/markdown",
+	".mdx":        "text/markdown",
+	".pdf":        "application/pdf",
+	".html":       "text/html",
+	".htm":        "text/html",
+	".csv":        "text/csv",
+	".xml":        "application/xml",
+	".vtt":        "text/vtt",
+	".properties": "text/plain",
+	".epub":       "application/epub+zip",
+	".eml":        "message/rfc822",
+	".msg":        "application/vnd.ms-outlook",
+	".doc":        "application/msword",
+	".docx":       "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xls":        "application/vnd.ms-excel",
+	".xlsx":       "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".ppt":        "application/vnd.ms-powerpoint",
+	".pptx":       "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+// detectContentType 优先根据扩展名判断 MIME 类型，无法判断时读取文件头识别
+func detectContentType(filename string, r *bufio.Reader) string {
+	ext := strings.ToLower(path.Ext(filename))
+	if contentType, ok := documentContentTypes[ext]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+	head, _ := r.Peek(512)
+	return http.DetectContentType(head)
+}
+
+// readerSize 返回 reader 的剩余字节数，无法得知时返回 0
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }: // bytes.Reader、strings.Reader、bytes.Buffer
+		return int64(v.Len())
+	case interface{ Stat() (fs.FileInfo, error) }: // os.File、fs.File
+		if info, err := v.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return 0
+		}
+		return end - cur
+	}
+	return 0
+}
+
+// progressReader 统计已读取的字节数，回调上传进度并在超过 limit 时返回 ErrFileTooLarge
+type progressReader struct {
+	r        io.Reader
+	limit    int64
+	progress func(UploadProgress)
+	state    UploadProgress
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.state.Written += int64(n)
+	if p.limit > 0 && p.state.Written > p.limit {
+		return n, fmt.Errorf("%s exceeds limit of %d bytes: %w", p.state.Filename, p.limit, ErrFileTooLarge)
+	}
+	if n > 0 && p.progress != nil {
+		p.progress(p.state)
+	}
+	return n, err
+}
+
+// documentFile 上传文档时的文件及上传选项
+type documentFile struct {
+	Filename    string
+	Body        io.Reader
+	Size        int64
+	MaxSize     int64
+	ContentType string
+	Progress    func(UploadProgress)
+}
+
+// UploadProgress 文件上传进度
+type UploadProgress struct {
+	Filename string // 文件名
+	Written  int64  // 已发送的字节数
+	Total    int64  // 文件大小，无法得知时为 0
+}
+
+// UploadConfig 服务端文件上传限制，大小的单位为 MB
+type UploadConfig struct {
+	FileSizeLimit           int `json:"file_size_limit"`   // 文档大小限制
+	BatchCountLimit         int `json:"batch_count_limit"` // 单次批量上传的文件数量
+	ImageFileSizeLimit      int `json:"image_file_size_limit"`
+	VideoFileSizeLimit      int `json:"video_file_size_limit"`
+	AudioFileSizeLimit      int `json:"audio_file_size_limit"`
+	WorkflowFileUploadLimit int `json:"workflow_file_upload_limit"`
+}
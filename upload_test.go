@@ -0,0 +1,169 @@
+package dify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fzdwx/dify/difytest"
+)
+
+// patternReader 生成指定长度的内容，不暴露长度，模拟无法预知大小的流
+type patternReader struct {
+	remaining int
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	n := len(p)
+	if n > r.remaining {
+		n = r.remaining
+	}
+	for i := range p[:n] {
+		p[i] = 'a' + byte(i%26)
+	}
+	r.remaining -= n
+	return n, nil
+}
+
+func newUploadTestDataset(t *testing.T, opts ...difytest.Option) (Client, *difytest.Server, string) {
+	t.Helper()
+	c, server := newTestClient(t, opts...)
+	resp, err := c.CreateEmptyDataset(context.Background(), &CreateEmptyDatasetRequest{Name: "upload"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, server, resp.Result.ID
+}
+
+func TestCreateByFileStreamingProgress(t *testing.T) {
+	c, server, datasetID := newUploadTestDataset(t)
+	ctx := context.Background()
+	const size = 3 << 20
+	var last UploadProgress
+	calls := 0
+	resp, err := c.CreateByFile(ctx, &CreateByFileRequest{
+		DatasetsID: datasetID,
+		Filename:   "report.docx",
+		FileBody:   &patternReader{remaining: size},
+		FileSize:   size,
+		Progress: func(p UploadProgress) {
+			calls++
+			last = p
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal(resp.Message)
+	}
+	if calls < 2 || last.Written != size || last.Total != size || last.Filename != "report.docx" {
+		t.Errorf("Unexpected progress %+v after %d calls", last, calls)
+	}
+	docs := server.Documents(datasetID)
+	if len(docs) != 1 || len(docs[0].Content) != size {
+		t.Fatalf("Expected one document of %d bytes, got %+v", size, docs)
+	}
+	if want := "application/vnd.openxmlformats-officedocument.wordprocessingml.document"; docs[0].ContentType != want {
+		t.Errorf("Expected content type %s, got %s", want, docs[0].ContentType)
+	}
+	if docs[0].Data["indexing_technique"] == nil {
+		t.Errorf("Expected data form field to be sent, got %v", docs[0].Data)
+	}
+}
+
+func TestCreateByFileDetectsContentType(t *testing.T) {
+	c, server, datasetID := newUploadTestDataset(t)
+	_, err := c.CreateByFile(context.Background(), &CreateByFileRequest{
+		DatasetsID: datasetID,
+		Filename:   "scan",
+		FileBody:   strings.NewReader("%PDF-1.7\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs := server.Documents(datasetID); docs[0].ContentType != "application/pdf" {
+		t.Errorf("Expected sniffed application/pdf, got %s", docs[0].ContentType)
+	}
+}
+
+func TestCreateByFileSizeLimit(t *testing.T) {
+	c, server, datasetID := newUploadTestDataset(t, difytest.WithFileSizeLimit(1))
+	ctx := context.Background()
+
+	// 已知大小时在发送前拒绝
+	before := len(server.Requests())
+	_, err := c.CreateByFile(ctx, &CreateByFileRequest{
+		DatasetsID: datasetID,
+		Filename:   "big.txt",
+		FileBody:   bytes.NewReader(make([]byte, 2<<20)),
+	})
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Expected ErrFileTooLarge, got %v", err)
+	}
+	for _, r := range server.Requests()[before:] {
+		if strings.HasSuffix(r.Path, "/create-by-file") {
+			t.Error("Expected oversized file to be rejected before upload")
+		}
+	}
+
+	// 未知大小时在上传过程中中断，服务端不会创建文档
+	_, err = c.CreateByFile(ctx, &CreateByFileRequest{
+		DatasetsID: datasetID,
+		Filename:   "big.txt",
+		FileBody:   &patternReader{remaining: 2 << 20},
+	})
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Expected ErrFileTooLarge, got %v", err)
+	}
+
+	// MaxFileSize 比服务端限制更严格时优先使用
+	_, err = c.CreateByFile(ctx, &CreateByFileRequest{
+		DatasetsID:  datasetID,
+		Filename:    "small.txt",
+		FileBody:    strings.NewReader("0123456789"),
+		MaxFileSize: 4,
+	})
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Expected ErrFileTooLarge, got %v", err)
+	}
+	if docs := server.Documents(datasetID); len(docs) != 0 {
+		t.Errorf("Expected no documents, got %d", len(docs))
+	}
+}
+
+func TestCreateByFileUploadLimitLookup(t *testing.T) {
+	c, server, datasetID := newUploadTestDataset(t, difytest.WithFileSizeLimit(1))
+	server.FailNext(http.MethodGet, "/console/api/files/upload", http.StatusInternalServerError)
+
+	// 已取消的上传不影响上传限制的获取，获取失败后一段时间内不再重试
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = c.CreateByFile(canceled, &CreateByFileRequest{DatasetsID: datasetID, Filename: "a.txt", FileBody: strings.NewReader("alpha")})
+	for i := 0; i < 2; i++ {
+		resp, err := c.CreateByFile(context.Background(), &CreateByFileRequest{
+			DatasetsID: datasetID,
+			Filename:   "a.txt",
+			FileBody:   strings.NewReader("alpha"),
+		})
+		if err != nil || !resp.IsSuccess() {
+			t.Fatalf("Expected upload to succeed without the limit, got %v", err)
+		}
+	}
+	lookups := 0
+	for _, r := range server.Requests() {
+		if r.Method == http.MethodGet && r.Path == "/console/api/files/upload" {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("Expected failed limit lookup to be cached, got %d lookups", lookups)
+	}
+}
@@ -0,0 +1,136 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"resty.dev/v3"
+	"strings"
+)
+
+// WorkflowEvent 工作流流式响应的事件类型
+type WorkflowEvent string
+
+// 工作流流式响应的事件类型，为无类型常量，可以直接与 CallWorkflowChunkCompletionResponse.Event 比较
+const (
+	WorkflowEventWorkflowStarted  = "workflow_started"  // 工作流开始执行
+	WorkflowEventNodeStarted      = "node_started"      // 节点开始执行
+	WorkflowEventNodeFinished     = "node_finished"     // 节点执行结束（成功或失败）
+	WorkflowEventTextChunk        = "text_chunk"        // 文本片段
+	WorkflowEventWorkflowFinished = "workflow_finished" // 工作流执行结束（成功或失败）
+	WorkflowEventError            = "error"             // 流式输出过程中出现异常
+	WorkflowEventPing             = "ping"              // 每 10s 一次的保活事件
+)
+
+// RunDraftWorkflow 调试运行应用的工作流草稿，返回与 CallWorkflowAppStreaming 相同格式的事件流
+// 使用控制台登录态，无需创建应用 API 密钥
+func (c *client) RunDraftWorkflow(ctx context.Context, req *RunDraftWorkflowRequest) (chan *CallWorkflowChunkCompletionResponse, error) {
+	var resultErr error
+	inputs := req.Inputs
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+	}
+	resp, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			SetDoNotParseResponse(true).
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(&runDraftWorkflowInternalRequest{
+				Inputs: inputs,
+				Files:  req.Files,
+			}).
+			Post(fmt.Sprintf("/console/api/apps/%s/workflows/draft/run", req.AppID))
+
+		if err != nil {
+			return response, fmt.Errorf("failed to run draft workflow: %w", err)
+		}
+		if response.IsError() {
+			resultErr = readStreamError(response, "run draft workflow")
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return streamEvents[CallWorkflowChunkCompletionResponse](resp.Body), nil
+}
+
+// RunDraftWorkflowNode 使用给定输入调试运行工作流草稿中的单个节点
+// 事件通过 channel 返回：服务端以 SSE 返回时（例如迭代、循环节点）逐个转发，
+// 以 JSON 返回节点执行结果时转换为一个 node_finished 事件
+func (c *client) RunDraftWorkflowNode(ctx context.Context, req *RunDraftWorkflowNodeRequest) (chan *CallWorkflowChunkCompletionResponse, error) {
+	var resultErr error
+	inputs := req.Inputs
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+	}
+	resp, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			SetDoNotParseResponse(true).
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(&runDraftWorkflowInternalRequest{
+				Inputs: inputs,
+			}).
+			Post(fmt.Sprintf("/console/api/apps/%s/workflows/draft/nodes/%s/run", req.AppID, req.NodeID))
+
+		if err != nil {
+			return response, fmt.Errorf("failed to run draft workflow node: %w", err)
+		}
+		if response.IsError() {
+			resultErr = readStreamError(response, "run draft workflow node")
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	if strings.HasPrefix(resp.Header().Get("Content-Type"), "text/event-stream") {
+		return streamEvents[CallWorkflowChunkCompletionResponse](resp.Body), nil
+	}
+
+	defer resp.Body.Close()
+	var data WorkflowData
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode draft workflow node result: %w", err)
+	}
+	events := make(chan *CallWorkflowChunkCompletionResponse, 1)
+	events <- &CallWorkflowChunkCompletionResponse{
+		Event: WorkflowEventNodeFinished,
+		Data:  data,
+	}
+	close(events)
+	return events, nil
+}
+
+// RunDraftWorkflowRequest 调试运行工作流草稿请求
+type RunDraftWorkflowRequest struct {
+	AppID  string                 // 应用ID
+	Inputs map[string]interface{} // 开始节点的输入变量
+	Files  []InputFile            // 上传的文件
+}
+
+// RunDraftWorkflowNodeRequest 调试运行单个节点请求
+type RunDraftWorkflowNodeRequest struct {
+	AppID  string                 // 应用ID
+	NodeID string                 // 节点ID
+	Inputs map[string]interface{} // 节点输入，key 为节点引用的变量（如 "#start.query#" 对应的变量名）
+}
+
+// runDraftWorkflowInternalRequest 内部使用的调试运行请求结构
+type runDraftWorkflowInternalRequest struct {
+	Inputs map[string]interface{} `json:"inputs"`
+	Files  []InputFile            `json:"files,omitempty"`
+}
@@ -0,0 +1,105 @@
+package dify
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRunDraftWorkflow(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	appResp, err := c.CreateChatApp(ctx, &CreateChatAppRequest{Name: "draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	appID := appResp.Result.ID
+
+	events, err := c.RunDraftWorkflow(ctx, &RunDraftWorkflowRequest{
+		AppID:  appID,
+		Inputs: map[string]interface{}{"query": "hi"},
+	})
+	if err != nil {
+		t.Fatal("Failed to run draft workflow:", err)
+	}
+	requests := server.Requests()
+	if last := requests[len(requests)-1]; last.Method != "POST" || last.Path != "/console/api/apps/"+appID+"/workflows/draft/run" {
+		t.Errorf("Unexpected request %s %s", last.Method, last.Path)
+	}
+
+	var started, finished []WorkflowData
+	var result *CallWorkflowChunkCompletionResponse
+	for event := range events {
+		switch event.EventType() {
+		case WorkflowEventWorkflowStarted:
+			if event.Data.Inputs["query"] != "hi" {
+				t.Errorf("Expected inputs in request body, got %v", event.Data.Inputs)
+			}
+		case WorkflowEventNodeStarted:
+			started = append(started, event.Data)
+		case WorkflowEventNodeFinished:
+			finished = append(finished, event.Data)
+		case WorkflowEventWorkflowFinished:
+			result = event
+		}
+	}
+	if len(started) != 2 || len(finished) != 2 {
+		t.Fatalf("Expected 2 node_started and 2 node_finished events, got %d and %d", len(started), len(finished))
+	}
+	if node := finished[1]; node.NodeID != "llm" || node.NodeType != NodeTypeLLM || node.Index != 2 || node.Status != "succeeded" {
+		t.Errorf("Unexpected node_finished data: %+v", node)
+	}
+	if result == nil || result.Data.Outputs["query"] != "hi" {
+		t.Fatalf("Expected workflow_finished with outputs, got %+v", result)
+	}
+}
+
+func TestRunDraftWorkflowNode(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	appResp, err := c.CreateChatApp(ctx, &CreateChatAppRequest{Name: "draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	appID := appResp.Result.ID
+
+	// 以 JSON 返回的节点结果转换为一个 node_finished 事件
+	events, err := c.RunDraftWorkflowNode(ctx, &RunDraftWorkflowNodeRequest{
+		AppID:  appID,
+		NodeID: "code",
+		Inputs: map[string]interface{}{"x": 1},
+	})
+	if err != nil {
+		t.Fatal("Failed to run draft workflow node:", err)
+	}
+	var got []*CallWorkflowChunkCompletionResponse
+	for event := range events {
+		got = append(got, event)
+	}
+	if len(got) != 1 || got[0].EventType() != WorkflowEventNodeFinished {
+		t.Fatalf("Expected a single node_finished event, got %v", got)
+	}
+	if data := got[0].Data; data.NodeID != "code" || data.NodeType != NodeTypeCode || data.Inputs["x"] != float64(1) || data.Outputs["x"] != float64(1) {
+		t.Errorf("Unexpected node result: %+v", data)
+	}
+
+	// 迭代节点以 SSE 返回
+	events, err = c.RunDraftWorkflowNode(ctx, &RunDraftWorkflowNodeRequest{AppID: appID, NodeID: "iteration-1"})
+	if err != nil {
+		t.Fatal("Failed to run draft workflow node:", err)
+	}
+	var names []WorkflowEvent
+	for event := range events {
+		names = append(names, event.EventType())
+		if event.Data.NodeID != "iteration-1" {
+			t.Errorf("Unexpected node id '%s'", event.Data.NodeID)
+		}
+	}
+	if len(names) != 2 || names[0] != WorkflowEventNodeStarted || names[1] != WorkflowEventNodeFinished {
+		t.Errorf("Expected node_started and node_finished, got %v", names)
+	}
+
+	_, err = c.RunDraftWorkflowNode(ctx, &RunDraftWorkflowNodeRequest{AppID: "missing", NodeID: "code"})
+	if err == nil {
+		t.Error("Expected error for unknown app")
+	}
+}
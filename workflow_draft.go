@@ -0,0 +1,242 @@
+package dify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"resty.dev/v3"
+)
+
+// ErrDraftWorkflowNotSync 保存草稿时 hash 与服务端不一致，说明草稿已被他人修改，需要重新获取后再保存
+var ErrDraftWorkflowNotSync = errors.New("draft workflow not sync")
+
+// GetDraftWorkflow 获取应用的工作流草稿
+func (c *client) GetDraftWorkflow(ctx context.Context, req *GetDraftWorkflowRequest) (*Response[DraftWorkflow], error) {
+	var resultErr error
+	var resp = &DraftWorkflow{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetResult(&resp).
+			Get(fmt.Sprintf("/console/api/apps/%s/workflows/draft", req.AppID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to get draft workflow: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to get draft workflow with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[DraftWorkflow](finalResponse, resp), nil
+}
+
+// SaveDraftWorkflow 保存应用的工作流草稿
+// Hash 需要传入获取草稿时服务端返回的 hash，不一致时返回 ErrDraftWorkflowNotSync
+func (c *client) SaveDraftWorkflow(ctx context.Context, req *SaveDraftWorkflowRequest) (*Response[SaveDraftWorkflowResponse], error) {
+	var resultErr error
+	var resp = &SaveDraftWorkflowResponse{}
+	var finalResponse *resty.Response
+
+	features := req.Features
+	if features == nil {
+		features = map[string]interface{}{}
+	}
+	environmentVariables := req.EnvironmentVariables
+	if environmentVariables == nil {
+		environmentVariables = []EnvironmentVariable{}
+	}
+	conversationVariables := req.ConversationVariables
+	if conversationVariables == nil {
+		conversationVariables = []ConversationVariable{}
+	}
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(&saveDraftWorkflowInternalRequest{
+				Graph:                 req.Graph,
+				Features:              features,
+				Hash:                  req.Hash,
+				EnvironmentVariables:  environmentVariables,
+				ConversationVariables: conversationVariables,
+			}).
+			SetResult(&resp).
+			Post(fmt.Sprintf("/console/api/apps/%s/workflows/draft", req.AppID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to save draft workflow: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			if errorCode(response) == "draft_workflow_not_sync" {
+				resultErr = fmt.Errorf("failed to save draft workflow: %w", ErrDraftWorkflowNotSync)
+			} else {
+				resultErr = fmt.Errorf("failed to save draft workflow with status %d: %s", response.StatusCode(), response.String())
+			}
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[SaveDraftWorkflowResponse](finalResponse, resp), nil
+}
+
+// PublishWorkflow 发布应用的工作流草稿
+func (c *client) PublishWorkflow(ctx context.Context, req *PublishWorkflowRequest) (*Response[PublishWorkflowResponse], error) {
+	var resultErr error
+	var resp = &PublishWorkflowResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		response, err := c.console().
+			WithContext(ctx).
+			SetContentType("application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Post(fmt.Sprintf("/console/api/apps/%s/workflows/publish", req.AppID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to publish workflow: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to publish workflow with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[PublishWorkflowResponse](finalResponse, resp), nil
+}
+
+// GetDraftWorkflowRequest 获取工作流草稿请求
+type GetDraftWorkflowRequest struct {
+	AppID string // 应用ID
+}
+
+// DraftWorkflow 工作流草稿
+type DraftWorkflow struct {
+	ID                    string                 `json:"id"`
+	Graph                 WorkflowGraph          `json:"graph"`
+	Features              map[string]interface{} `json:"features"`
+	Hash                  string                 `json:"hash"` // 保存草稿时需要回传，用于乐观并发控制
+	Version               string                 `json:"version"`
+	EnvironmentVariables  []EnvironmentVariable  `json:"environment_variables"`
+	ConversationVariables []ConversationVariable `json:"conversation_variables"`
+	CreatedBy             *WorkflowAccount       `json:"created_by"`
+	CreatedAt             int64                  `json:"created_at"`
+	UpdatedBy             *WorkflowAccount       `json:"updated_by"`
+	UpdatedAt             int64                  `json:"updated_at"`
+}
+
+// SaveDraftWorkflowRequest 保存工作流草稿请求
+type SaveDraftWorkflowRequest struct {
+	AppID                 string                 // 应用ID
+	Graph                 WorkflowGraph          // 工作流图
+	Features              map[string]interface{} // 应用功能配置（开场白、文件上传等），建议原样回传获取到的值
+	Hash                  string                 // 获取草稿时返回的 hash
+	EnvironmentVariables  []EnvironmentVariable  // 环境变量
+	ConversationVariables []ConversationVariable // 会话变量（仅 advanced-chat）
+}
+
+// saveDraftWorkflowInternalRequest 内部使用的保存草稿请求结构
+type saveDraftWorkflowInternalRequest struct {
+	Graph                 WorkflowGraph          `json:"graph"`
+	Features              map[string]interface{} `json:"features"`
+	Hash                  string                 `json:"hash,omitempty"`
+	EnvironmentVariables  []EnvironmentVariable  `json:"environment_variables"`
+	ConversationVariables []ConversationVariable `json:"conversation_variables"`
+}
+
+// SaveDraftWorkflowResponse 保存工作流草稿响应
+type SaveDraftWorkflowResponse struct {
+	Result    string `json:"result"`
+	Hash      string `json:"hash"` // 保存后的新 hash
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// PublishWorkflowRequest 发布工作流请求
+type PublishWorkflowRequest struct {
+	AppID         string `json:"-"`                        // 应用ID，不包含在JSON中
+	MarkedName    string `json:"marked_name,omitempty"`    // 版本名称
+	MarkedComment string `json:"marked_comment,omitempty"` // 版本说明
+}
+
+// PublishWorkflowResponse 发布工作流响应
+type PublishWorkflowResponse struct {
+	Result    string `json:"result"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// EnvironmentVariableType 环境变量类型
+type EnvironmentVariableType string
+
+const (
+	EnvironmentVariableTypeString EnvironmentVariableType = "string"
+	EnvironmentVariableTypeNumber EnvironmentVariableType = "number"
+	EnvironmentVariableTypeSecret EnvironmentVariableType = "secret" // 导出 DSL 时默认不包含值
+)
+
+// EnvironmentVariable 工作流环境变量
+type EnvironmentVariable struct {
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Value       interface{}             `json:"value"`
+	ValueType   EnvironmentVariableType `json:"value_type"`
+	Description string                  `json:"description"`
+}
+
+// ConversationVariable 会话变量
+type ConversationVariable struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Value       interface{} `json:"value"`
+	ValueType   string      `json:"value_type"` // string / number / object / array[string] / array[number] / array[object]
+	Description string      `json:"description"`
+}
@@ -0,0 +1,434 @@
+package dify
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WorkflowGraph 工作流图
+type WorkflowGraph struct {
+	Nodes    []WorkflowNode    `json:"nodes"`
+	Edges    []WorkflowEdge    `json:"edges"`
+	Viewport *WorkflowViewport `json:"viewport,omitempty"`
+}
+
+// WorkflowViewport 画布视口
+type WorkflowViewport struct {
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	Zoom float64 `json:"zoom"`
+}
+
+// WorkflowPosition 节点在画布上的位置
+type WorkflowPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// WorkflowNode 工作流节点
+// Data 保存节点的原始配置，可通过 DecodeData 解析为 StartNodeData、LLMNodeData 等类型化结构，
+// 未显式建模的字段（宽高、选中状态等）保存在 Extra 中，保证获取后原样保存不丢失信息
+type WorkflowNode struct {
+	ID       string                     `json:"id"`
+	Type     string                     `json:"type"` // 画布节点类型，通常为 custom
+	Position WorkflowPosition           `json:"position"`
+	Data     json.RawMessage            `json:"data"`
+	Extra    map[string]json.RawMessage `json:"-"`
+}
+
+// NewWorkflowNode 使用类型化的节点配置创建节点
+func NewWorkflowNode(id string, data NodeData, position WorkflowPosition) (WorkflowNode, error) {
+	node := WorkflowNode{
+		ID:       id,
+		Type:     "custom",
+		Position: position,
+	}
+	if err := node.SetData(data); err != nil {
+		return WorkflowNode{}, err
+	}
+	return node, nil
+}
+
+// NodeType 返回节点的业务类型（data.type）
+func (n *WorkflowNode) NodeType() NodeType {
+	var base NodeDataBase
+	_ = json.Unmarshal(n.Data, &base)
+	return base.Type
+}
+
+// DecodeData 将节点配置解析到 v 中，v 通常为 *LLMNodeData 等类型化结构
+func (n *WorkflowNode) DecodeData(v interface{}) error {
+	if err := json.Unmarshal(n.Data, v); err != nil {
+		return fmt.Errorf("failed to decode data of node %s: %w", n.ID, err)
+	}
+	return nil
+}
+
+// SetData 使用类型化的节点配置更新节点的 Data，data.type 总是按配置的类型填充
+// 类型化结构建模的字段被整体替换，其余字段（如新版本 Dify 增加的节点选项）保留原值，
+// 因此 DecodeData → 修改 → SetData 不会丢失未建模的配置
+func (n *WorkflowNode) SetData(data NodeData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode data of node %s: %w", n.ID, err)
+	}
+	typed := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return fmt.Errorf("failed to encode data of node %s: %w", n.ID, err)
+	}
+	fields := map[string]json.RawMessage{}
+	if len(n.Data) > 0 {
+		// 原始配置不是对象时直接替换
+		_ = json.Unmarshal(n.Data, &fields)
+	}
+	// 先删除建模的字段，使 omitempty 字段置空后不会沿用旧值
+	for _, key := range jsonFieldNames(reflect.TypeOf(data)) {
+		delete(fields, key)
+	}
+	for key, value := range typed {
+		fields[key] = value
+	}
+	fields["type"], _ = json.Marshal(data.nodeType())
+	n.Data, err = json.Marshal(fields)
+	return err
+}
+
+// jsonFieldNames 返回结构体序列化时的字段名，包含嵌入结构体的字段
+func jsonFieldNames(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			names = append(names, jsonFieldNames(field.Type)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func (n WorkflowNode) MarshalJSON() ([]byte, error) {
+	type node WorkflowNode
+	return marshalWithExtra(node(n), n.Extra)
+}
+
+func (n *WorkflowNode) UnmarshalJSON(data []byte) error {
+	type node WorkflowNode
+	var v node
+	extra, err := unmarshalWithExtra(data, &v, "id", "type", "position", "data")
+	if err != nil {
+		return err
+	}
+	*n = WorkflowNode(v)
+	n.Extra = extra
+	return nil
+}
+
+// WorkflowEdge 工作流连线
+type WorkflowEdge struct {
+	ID           string                     `json:"id"`
+	Type         string                     `json:"type"` // 画布连线类型，通常为 custom
+	Source       string                     `json:"source"`
+	SourceHandle string                     `json:"sourceHandle"` // 条件分支节点为 case_id 或 false，其他节点为 source
+	Target       string                     `json:"target"`
+	TargetHandle string                     `json:"targetHandle"`
+	Data         map[string]interface{}     `json:"data,omitempty"`
+	Extra        map[string]json.RawMessage `json:"-"`
+}
+
+// NewWorkflowEdge 创建从 source 节点到 target 节点的连线
+func NewWorkflowEdge(source, sourceHandle, target string) WorkflowEdge {
+	if sourceHandle == "" {
+		sourceHandle = "source"
+	}
+	return WorkflowEdge{
+		ID:           fmt.Sprintf("%s-%s-%s-target", source, sourceHandle, target),
+		Type:         "custom",
+		Source:       source,
+		SourceHandle: sourceHandle,
+		Target:       target,
+		TargetHandle: "target",
+		Data:         map[string]interface{}{},
+	}
+}
+
+func (e WorkflowEdge) MarshalJSON() ([]byte, error) {
+	type edge WorkflowEdge
+	return marshalWithExtra(edge(e), e.Extra)
+}
+
+func (e *WorkflowEdge) UnmarshalJSON(data []byte) error {
+	type edge WorkflowEdge
+	var v edge
+	extra, err := unmarshalWithExtra(data, &v, "id", "type", "source", "sourceHandle", "target", "targetHandle", "data")
+	if err != nil {
+		return err
+	}
+	*e = WorkflowEdge(v)
+	e.Extra = extra
+	return nil
+}
+
+// marshalWithExtra 序列化 v，并把 extra 中未显式建模的字段合并到结果中
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalWithExtra 反序列化到 v，返回 known 之外的字段
+func unmarshalWithExtra(data []byte, v interface{}, known ...string) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, key := range known {
+		delete(fields, key)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// NodeType 工作流节点类型
+type NodeType string
+
+const (
+	NodeTypeStart              NodeType = "start"
+	NodeTypeEnd                NodeType = "end"
+	NodeTypeAnswer             NodeType = "answer"
+	NodeTypeLLM                NodeType = "llm"
+	NodeTypeKnowledgeRetrieval NodeType = "knowledge-retrieval"
+	NodeTypeCode               NodeType = "code"
+	NodeTypeHTTPRequest        NodeType = "http-request"
+	NodeTypeIfElse             NodeType = "if-else"
+	NodeTypeTemplateTransform  NodeType = "template-transform"
+	NodeTypeVariableAggregator NodeType = "variable-aggregator"
+	NodeTypeQuestionClassifier NodeType = "question-classifier"
+	NodeTypeParameterExtractor NodeType = "parameter-extractor"
+	NodeTypeTool               NodeType = "tool"
+	NodeTypeIteration          NodeType = "iteration"
+	NodeTypeLoop               NodeType = "loop"
+)
+
+// NodeData 类型化的节点配置
+type NodeData interface {
+	nodeType() NodeType
+}
+
+// NodeDataBase 所有节点共有的配置
+type NodeDataBase struct {
+	Type  NodeType `json:"type"`
+	Title string   `json:"title"`
+	Desc  string   `json:"desc"`
+}
+
+// VariableSelector 引用其他节点输出的变量，例如 {"variable": "text", "value_selector": ["llm", "text"]}
+type VariableSelector struct {
+	Variable      string   `json:"variable"`
+	ValueSelector []string `json:"value_selector"`
+}
+
+// StartNodeData 开始节点
+type StartNodeData struct {
+	NodeDataBase
+	Variables []StartVariable `json:"variables"`
+}
+
+func (StartNodeData) nodeType() NodeType { return NodeTypeStart }
+
+// StartVariable 开始节点的输入变量
+type StartVariable struct {
+	Variable              string   `json:"variable"`
+	Label                 string   `json:"label"`
+	Type                  string   `json:"type"` // text-input / paragraph / select / number / file / file-list
+	Required              bool     `json:"required"`
+	MaxLength             int      `json:"max_length,omitempty"`
+	Options               []string `json:"options,omitempty"`
+	AllowedFileTypes      []string `json:"allowed_file_types,omitempty"`
+	AllowedFileExtensions []string `json:"allowed_file_extensions,omitempty"`
+	AllowedUploadMethods  []string `json:"allowed_file_upload_methods,omitempty"`
+}
+
+// LLMNodeData LLM 节点
+type LLMNodeData struct {
+	NodeDataBase
+	Model          ModelConfig        `json:"model"`
+	PromptTemplate []PromptMessage    `json:"prompt_template"`
+	Context        LLMContextConfig   `json:"context"`
+	Vision         LLMVisionConfig    `json:"vision"`
+	Memory         *LLMMemoryConfig   `json:"memory,omitempty"`
+	Variables      []VariableSelector `json:"variables"`
+}
+
+func (LLMNodeData) nodeType() NodeType { return NodeTypeLLM }
+
+// PromptMessage 提示词消息
+type PromptMessage struct {
+	ID   string `json:"id,omitempty"`
+	Role string `json:"role"` // system / user / assistant
+	Text string `json:"text"`
+}
+
+// LLMContextConfig LLM 节点的上下文（通常引用知识检索节点的 result）
+type LLMContextConfig struct {
+	Enabled          bool     `json:"enabled"`
+	VariableSelector []string `json:"variable_selector"`
+}
+
+// LLMVisionConfig LLM 节点的视觉配置
+type LLMVisionConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// LLMMemoryConfig LLM 节点的记忆配置（仅 advanced-chat）
+type LLMMemoryConfig struct {
+	Window struct {
+		Enabled bool `json:"enabled"`
+		Size    int  `json:"size"`
+	} `json:"window"`
+	QueryPromptTemplate string `json:"query_prompt_template,omitempty"`
+}
+
+// KnowledgeRetrievalNodeData 知识检索节点
+type KnowledgeRetrievalNodeData struct {
+	NodeDataBase
+	QueryVariableSelector   []string                 `json:"query_variable_selector"`
+	DatasetIDs              []string                 `json:"dataset_ids"`
+	RetrievalMode           string                   `json:"retrieval_mode"` // single / multiple
+	MultipleRetrievalConfig *MultipleRetrievalConfig `json:"multiple_retrieval_config,omitempty"`
+
+	MetadataFilteringMode       MetadataFilteringMode        `json:"metadata_filtering_mode,omitempty"`
+	MetadataFilteringConditions *MetadataFilteringConditions `json:"metadata_filtering_conditions,omitempty"`
+}
+
+func (KnowledgeRetrievalNodeData) nodeType() NodeType { return NodeTypeKnowledgeRetrieval }
+
+// MultipleRetrievalConfig 多路召回配置
+type MultipleRetrievalConfig struct {
+	TopK            int      `json:"top_k"`
+	ScoreThreshold  *float64 `json:"score_threshold"`
+	RerankingEnable bool     `json:"reranking_enable"`
+	RerankingMode   string   `json:"reranking_mode,omitempty"` // reranking_model / weighted_score
+	RerankingModel  *struct {
+		Provider string `json:"provider"`
+		Model    string `json:"model"`
+	} `json:"reranking_model,omitempty"`
+}
+
+// CodeNodeData 代码执行节点
+type CodeNodeData struct {
+	NodeDataBase
+	CodeLanguage string                `json:"code_language"` // python3 / javascript
+	Code         string                `json:"code"`
+	Variables    []VariableSelector    `json:"variables"`
+	Outputs      map[string]CodeOutput `json:"outputs"`
+}
+
+func (CodeNodeData) nodeType() NodeType { return NodeTypeCode }
+
+// CodeOutput 代码节点的输出变量定义
+type CodeOutput struct {
+	Type     string      `json:"type"` // string / number / object / array[string] / array[number] / array[object]
+	Children interface{} `json:"children"`
+}
+
+// HTTPRequestNodeData HTTP 请求节点
+type HTTPRequestNodeData struct {
+	NodeDataBase
+	Method        string                   `json:"method"` // get / post / put / patch / delete / head
+	URL           string                   `json:"url"`
+	Headers       string                   `json:"headers"` // 每行一个 key:value
+	Params        string                   `json:"params"`  // 每行一个 key:value
+	Body          HTTPRequestBody          `json:"body"`
+	Authorization HTTPRequestAuthorization `json:"authorization"`
+	Timeout       *HTTPRequestTimeout      `json:"timeout,omitempty"`
+	Variables     []VariableSelector       `json:"variables"`
+}
+
+func (HTTPRequestNodeData) nodeType() NodeType { return NodeTypeHTTPRequest }
+
+// HTTPRequestBody HTTP 请求体
+type HTTPRequestBody struct {
+	Type string      `json:"type"` // none / form-data / x-www-form-urlencoded / raw-text / json / binary
+	Data interface{} `json:"data"`
+}
+
+// HTTPRequestAuthorization HTTP 请求鉴权
+type HTTPRequestAuthorization struct {
+	Type   string                 `json:"type"` // no-auth / api-key
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// HTTPRequestTimeout HTTP 请求超时（秒）
+type HTTPRequestTimeout struct {
+	Connect int `json:"connect"`
+	Read    int `json:"read"`
+	Write   int `json:"write"`
+}
+
+// IfElseNodeData 条件分支节点
+type IfElseNodeData struct {
+	NodeDataBase
+	Cases []IfElseCase `json:"cases"`
+}
+
+func (IfElseNodeData) nodeType() NodeType { return NodeTypeIfElse }
+
+// IfElseCase 条件分支，CaseID 同时作为出边的 sourceHandle
+type IfElseCase struct {
+	CaseID          string            `json:"case_id"`
+	LogicalOperator string            `json:"logical_operator"` // and / or
+	Conditions      []IfElseCondition `json:"conditions"`
+}
+
+// IfElseCondition 分支条件
+type IfElseCondition struct {
+	ID                 string   `json:"id"`
+	VariableSelector   []string `json:"variable_selector"`
+	ComparisonOperator string   `json:"comparison_operator"` // contains / is / empty / = / > 等
+	Value              string   `json:"value"`
+	VarType            string   `json:"varType,omitempty"`
+}
+
+// EndNodeData 结束节点
+type EndNodeData struct {
+	NodeDataBase
+	Outputs []VariableSelector `json:"outputs"`
+}
+
+func (EndNodeData) nodeType() NodeType { return NodeTypeEnd }
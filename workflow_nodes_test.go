@@ -0,0 +1,91 @@
+package dify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWorkflowGraphRoundTrip(t *testing.T) {
+	raw := `{"nodes":[{"id":"1","type":"custom","position":{"x":80,"y":282},"width":244,"height":90,"selected":false,` +
+		`"data":{"type":"start","title":"开始","desc":"","variables":[{"variable":"query","label":"问题","type":"paragraph","required":true,"max_length":48}]}}],` +
+		`"edges":[{"id":"1-source-2-target","type":"custom","source":"1","sourceHandle":"source","target":"2","targetHandle":"target","zIndex":0,"data":{"sourceType":"start","targetType":"llm"}}]}`
+
+	var graph WorkflowGraph
+	if err := json.Unmarshal([]byte(raw), &graph); err != nil {
+		t.Fatal(err)
+	}
+	node := graph.Nodes[0]
+	if node.NodeType() != NodeTypeStart {
+		t.Fatalf("Expected start node, got '%s'", node.NodeType())
+	}
+	var start StartNodeData
+	if err := node.DecodeData(&start); err != nil {
+		t.Fatal(err)
+	}
+	if len(start.Variables) != 1 || start.Variables[0].Variable != "query" {
+		t.Errorf("Unexpected start variables: %+v", start.Variables)
+	}
+
+	data, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]interface{}
+	_ = json.Unmarshal(data, &got)
+	_ = json.Unmarshal([]byte(raw), &want)
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("Graph changed after round trip:\nwant %s\ngot  %s", wantJSON, gotJSON)
+	}
+}
+
+func TestNewWorkflowNode(t *testing.T) {
+	node, err := NewWorkflowNode("code", CodeNodeData{
+		NodeDataBase: NodeDataBase{Title: "代码执行"},
+		CodeLanguage: "python3",
+		Code:         "def main(x: int) -> dict:\n    return {'y': x + 1}\n",
+		Variables:    []VariableSelector{{Variable: "x", ValueSelector: []string{"start", "x"}}},
+		Outputs:      map[string]CodeOutput{"y": {Type: "number"}},
+	}, WorkflowPosition{X: 380, Y: 282})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.NodeType() != NodeTypeCode {
+		t.Errorf("Expected code node, got '%s'", node.NodeType())
+	}
+}
+
+func TestSetDataKeepsUnknownFields(t *testing.T) {
+	raw := `{"id":"llm","type":"custom","position":{"x":0,"y":0},"data":{"type":"llm","title":"LLM","desc":"",` +
+		`"model":{"provider":"openai","name":"gpt-4o","mode":"chat","completion_params":{}},"prompt_template":[],` +
+		`"context":{"enabled":false,"variable_selector":[]},"vision":{"enabled":false},` +
+		`"memory":{"window":{"enabled":false,"size":10}},"structured_output_enabled":true,"reasoning_format":"separated"}}`
+	var node WorkflowNode
+	if err := json.Unmarshal([]byte(raw), &node); err != nil {
+		t.Fatal(err)
+	}
+	var llm LLMNodeData
+	if err := node.DecodeData(&llm); err != nil {
+		t.Fatal(err)
+	}
+	llm.Title = "总结"
+	llm.Memory = nil
+	if err := node.SetData(llm); err != nil {
+		t.Fatal(err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(node.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data["title"] != "总结" || data["type"] != "llm" {
+		t.Errorf("Expected typed fields to be updated, got %v", data)
+	}
+	if data["structured_output_enabled"] != true || data["reasoning_format"] != "separated" {
+		t.Errorf("Expected unknown fields to be kept, got %v", data)
+	}
+	if _, ok := data["memory"]; ok {
+		t.Errorf("Expected cleared memory to be removed, got %v", data["memory"])
+	}
+}
@@ -0,0 +1,259 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"resty.dev/v3"
+	"strconv"
+	"time"
+)
+
+// GetWorkflowRun 获取工作流执行情况
+// 可用于流式调用中断后，根据 workflow_run_id 查询最终执行结果
+func (a *appClient) GetWorkflowRun(ctx context.Context, req *GetWorkflowRunRequest) (*Response[WorkflowRun], error) {
+	var resp = &WorkflowRun{}
+	response, err := a.request().
+		WithContext(ctx).
+		SetResult(&resp).
+		Get(fmt.Sprintf("/workflows/run/%s", req.WorkflowRunID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[WorkflowRun](response, resp), nil
+}
+
+// ListWorkflowLogs 分页获取工作流日志
+func (a *appClient) ListWorkflowLogs(ctx context.Context, req *ListWorkflowLogsRequest) (*Response[ListWorkflowLogsResponse], error) {
+	var resp = &ListWorkflowLogsResponse{}
+	request := a.request().
+		WithContext(ctx).
+		SetResult(&resp)
+	if req.Keyword != "" {
+		request.SetQueryParam("keyword", req.Keyword)
+	}
+	if req.Status != "" {
+		request.SetQueryParam("status", string(req.Status))
+	}
+	if req.Page > 0 {
+		request.SetQueryParam("page", strconv.Itoa(req.Page))
+	}
+	if req.Limit > 0 {
+		request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+	}
+	if !req.CreatedAfter.IsZero() {
+		request.SetQueryParam("created_at__after", req.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !req.CreatedBefore.IsZero() {
+		request.SetQueryParam("created_at__before", req.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+	response, err := request.Get("/workflows/logs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow logs: %w", err)
+	}
+	if err := checkAppAuth(response); err != nil {
+		return nil, err
+	}
+	return buildResponse[ListWorkflowLogsResponse](response, resp), nil
+}
+
+// WorkflowRunStatus 工作流执行状态
+type WorkflowRunStatus string
+
+const (
+	WorkflowRunStatusRunning          WorkflowRunStatus = "running"           // 执行中
+	WorkflowRunStatusSucceeded        WorkflowRunStatus = "succeeded"         // 成功
+	WorkflowRunStatusFailed           WorkflowRunStatus = "failed"            // 失败
+	WorkflowRunStatusStopped          WorkflowRunStatus = "stopped"           // 已停止
+	WorkflowRunStatusPartialSucceeded WorkflowRunStatus = "partial-succeeded" // 部分成功
+)
+
+// GetWorkflowRunRequest 获取工作流执行情况请求
+type GetWorkflowRunRequest struct {
+	WorkflowRunID string // 工作流执行 ID，可从调用工作流的响应中获取
+	Token         string // 应用访问令牌
+}
+
+// WorkflowRun 工作流执行详情
+type WorkflowRun struct {
+	ID          string            `json:"id"`
+	WorkflowID  string            `json:"workflow_id"`
+	Status      WorkflowRunStatus `json:"status"`
+	Inputs      JSONObject        `json:"inputs"`  // 接口返回 JSON 字符串，自动解析为对象
+	Outputs     JSONObject        `json:"outputs"` // 同上，工作流未结束时为空
+	Error       string            `json:"error"`
+	TotalSteps  int64             `json:"total_steps"`
+	TotalTokens int64             `json:"total_tokens"`
+	ElapsedTime float64           `json:"elapsed_time"` // 耗时（秒）
+	CreatedAt   int64             `json:"created_at"`
+	FinishedAt  int64             `json:"finished_at"`
+}
+
+// JSONObject JSON 对象，兼容以 JSON 字符串编码的对象
+// 工作流执行详情接口把 inputs 和 outputs 作为字符串返回，如 "{\"query\": \"hi\"}"
+type JSONObject map[string]interface{}
+
+func (o *JSONObject) UnmarshalJSON(data []byte) error {
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err == nil {
+		if encoded == "" {
+			*o = nil
+			return nil
+		}
+		data = []byte(encoded)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("failed to decode JSON object: %w", err)
+	}
+	*o = m
+	return nil
+}
+
+// ListWorkflowLogsRequest 获取工作流日志请求
+type ListWorkflowLogsRequest struct {
+	Keyword       string            // 关键字
+	Status        WorkflowRunStatus // 执行状态：succeeded / failed / stopped
+	Page          int               // 页码，默认 1
+	Limit         int               // 每页数量，默认 20
+	CreatedAfter  time.Time         // 仅返回该时间之后创建的日志
+	CreatedBefore time.Time         // 仅返回该时间之前创建的日志
+	Token         string            // 应用访问令牌
+}
+
+// ListWorkflowLogsResponse 工作流日志列表响应
+type ListWorkflowLogsResponse struct {
+	Page    int           `json:"page"`
+	Limit   int           `json:"limit"`
+	Total   int           `json:"total"`
+	HasMore bool          `json:"has_more"`
+	Data    []WorkflowLog `json:"data"`
+}
+
+// WorkflowLog 工作流日志
+type WorkflowLog struct {
+	ID               string              `json:"id"`
+	WorkflowRun      WorkflowLogRun      `json:"workflow_run"`
+	CreatedFrom      string              `json:"created_from"`
+	CreatedByRole    string              `json:"created_by_role"`
+	CreatedByAccount interface{}         `json:"created_by_account"`
+	CreatedByEndUser *WorkflowLogEndUser `json:"created_by_end_user"`
+	CreatedAt        int64               `json:"created_at"`
+}
+
+// WorkflowLogRun 日志中的工作流执行概要
+type WorkflowLogRun struct {
+	ID          string            `json:"id"`
+	Version     string            `json:"version"`
+	Status      WorkflowRunStatus `json:"status"`
+	Error       string            `json:"error"`
+	ElapsedTime float64           `json:"elapsed_time"`
+	TotalTokens int64             `json:"total_tokens"`
+	TotalSteps  int64             `json:"total_steps"`
+	CreatedAt   int64             `json:"created_at"`
+	FinishedAt  int64             `json:"finished_at"`
+}
+
+// WorkflowLogEndUser 触发工作流的终端用户
+type WorkflowLogEndUser struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	IsAnonymous bool   `json:"is_anonymous"`
+	SessionID   string `json:"session_id"`
+}
+
+// workflowRunPath 返回调用工作流的路径，指定 WorkflowID 时运行对应的已发布版本
+func workflowRunPath(req *CallWorkflowRequest) string {
+	if req.WorkflowID != "" {
+		return fmt.Sprintf("/workflows/%s/run", req.WorkflowID)
+	}
+	return "/workflows/run"
+}
+
+// ListPublishedWorkflows 分页获取应用已发布的工作流版本
+func (c *client) ListPublishedWorkflows(ctx context.Context, req *ListPublishedWorkflowsRequest) (*Response[ListPublishedWorkflowsResponse], error) {
+	var resultErr error
+	var resp = &ListPublishedWorkflowsResponse{}
+	var finalResponse *resty.Response
+
+	_, err := c.executeConsoleWithRetry(func() (*resty.Response, error) {
+		resultErr = nil
+		request := c.console().
+			WithContext(ctx).
+			SetResult(&resp)
+		if req.Page > 0 {
+			request.SetQueryParam("page", strconv.Itoa(req.Page))
+		}
+		if req.Limit > 0 {
+			request.SetQueryParam("limit", strconv.Itoa(req.Limit))
+		}
+		if req.NamedOnly {
+			request.SetQueryParam("named_only", "true")
+		}
+		response, err := request.Get(fmt.Sprintf("/console/api/apps/%s/workflows", req.AppID))
+
+		finalResponse = response
+
+		if err != nil {
+			resultErr = fmt.Errorf("failed to list published workflows: %w", err)
+			return response, err
+		}
+
+		if response.IsError() {
+			resultErr = fmt.Errorf("failed to list published workflows with status %d: %s", response.StatusCode(), response.String())
+			return response, nil // Don't return error here, let executeConsoleWithRetry handle 401
+		}
+
+		return response, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	return buildResponse[ListPublishedWorkflowsResponse](finalResponse, resp), nil
+}
+
+// ListPublishedWorkflowsRequest 获取已发布工作流版本请求
+type ListPublishedWorkflowsRequest struct {
+	AppID     string // 应用ID
+	Page      int    // 页码，默认 1
+	Limit     int    // 每页数量，默认 10
+	NamedOnly bool   // 仅返回已命名的版本
+}
+
+// ListPublishedWorkflowsResponse 已发布工作流版本列表响应
+type ListPublishedWorkflowsResponse struct {
+	Items   []PublishedWorkflow `json:"items"`
+	Page    int                 `json:"page"`
+	Limit   int                 `json:"limit"`
+	HasMore bool                `json:"has_more"`
+}
+
+// PublishedWorkflow 已发布的工作流版本
+type PublishedWorkflow struct {
+	ID            string           `json:"id"`
+	Version       string           `json:"version"` // 发布时间生成的版本号，草稿为 draft
+	Hash          string           `json:"hash"`
+	MarkedName    string           `json:"marked_name"`
+	MarkedComment string           `json:"marked_comment"`
+	CreatedBy     *WorkflowAccount `json:"created_by"`
+	CreatedAt     int64            `json:"created_at"`
+	UpdatedBy     *WorkflowAccount `json:"updated_by"`
+	UpdatedAt     int64            `json:"updated_at"`
+	ToolPublished bool             `json:"tool_published"`
+}
+
+// WorkflowAccount 工作流的创建/更新人
+type WorkflowAccount struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
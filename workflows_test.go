@@ -0,0 +1,167 @@
+package dify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWorkflowRunPath(t *testing.T) {
+	if got := workflowRunPath(&CallWorkflowRequest{}); got != "/workflows/run" {
+		t.Errorf("Expected latest published workflow path, got '%s'", got)
+	}
+	if got := workflowRunPath(&CallWorkflowRequest{WorkflowID: "wf-1"}); got != "/workflows/wf-1/run" {
+		t.Errorf("Expected pinned workflow path, got '%s'", got)
+	}
+}
+
+func TestWorkflowRunDecode(t *testing.T) {
+	// 与 Dify 文档中 GET /workflows/run/:workflow_run_id 的响应一致，inputs 和 outputs 为 JSON 字符串
+	payload := `{
+		"id": "b1ad3277-089e-42c6-9dff-6820d94fbc76",
+		"workflow_id": "19eff89f-ec03-4f75-b0fc-897e7effea02",
+		"status": "succeeded",
+		"inputs": "{\"sys.files\": [], \"sys.user_id\": \"abc-123\"}",
+		"outputs": "",
+		"error": null,
+		"total_steps": 3,
+		"total_tokens": 0,
+		"created_at": 1705407629,
+		"finished_at": 1727807631,
+		"elapsed_time": 30.098514399956912
+	}`
+	var run WorkflowRun
+	if err := json.Unmarshal([]byte(payload), &run); err != nil {
+		t.Fatal("Failed to decode workflow run:", err)
+	}
+	if run.Inputs["sys.user_id"] != "abc-123" {
+		t.Errorf("Expected inputs decoded from string, got %v", run.Inputs)
+	}
+	if run.Outputs != nil {
+		t.Errorf("Expected empty outputs, got %v", run.Outputs)
+	}
+
+	// 兼容直接返回对象的版本
+	if err := json.Unmarshal([]byte(`{"inputs": {"query": "hi"}, "outputs": null}`), &run); err != nil {
+		t.Fatal("Failed to decode workflow run:", err)
+	}
+	if run.Inputs["query"] != "hi" {
+		t.Errorf("Expected inputs decoded from object, got %v", run.Inputs)
+	}
+	if err := json.Unmarshal([]byte(`{"inputs": "not json"}`), &run); err == nil {
+		t.Error("Expected error for malformed inputs string")
+	}
+}
+
+func TestGetWorkflowRun(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	token := newTestAppToken(t, c)
+
+	runResp, err := c.CallWorkflowAppBlocking(ctx, &CallWorkflowRequest{
+		Inputs:       map[string]interface{}{"question": "hello"},
+		ResponseMode: ResponseModeBlocking,
+		User:         "test_user",
+		Token:        token,
+	})
+	if err != nil {
+		t.Fatal("Failed to call workflow app blocking:", err)
+	}
+
+	resp, err := c.GetWorkflowRun(ctx, &GetWorkflowRunRequest{WorkflowRunID: runResp.Result.WorkflowRunID, Token: token})
+	if err != nil {
+		t.Fatal("Failed to get workflow run:", err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal("Get workflow run failed:", resp.Message)
+	}
+	run := resp.Result
+	if run.ID != runResp.Result.WorkflowRunID || run.Status != WorkflowRunStatusSucceeded {
+		t.Errorf("Unexpected workflow run: %+v", run)
+	}
+	if run.Inputs["question"] != "hello" {
+		t.Errorf("Expected inputs to round trip, got %v", run.Inputs)
+	}
+	if run.Outputs["text"] == nil {
+		t.Errorf("Expected outputs to contain text, got %v", run.Outputs)
+	}
+
+	resp, err = c.GetWorkflowRun(ctx, &GetWorkflowRunRequest{WorkflowRunID: "missing", Token: token})
+	if err != nil {
+		t.Fatal("Failed to get workflow run:", err)
+	}
+	if resp.StatusCode() != 404 {
+		t.Errorf("Expected 404 for unknown run, got %d", resp.StatusCode())
+	}
+}
+
+func TestListWorkflowLogs(t *testing.T) {
+	c, server := newTestClient(t)
+	ctx := context.Background()
+	token := newTestAppToken(t, c)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.CallWorkflowAppBlocking(ctx, &CallWorkflowRequest{
+			Inputs: map[string]interface{}{"question": "hello"},
+			User:   "test_user",
+			Token:  token,
+		}); err != nil {
+			t.Fatal("Failed to call workflow app blocking:", err)
+		}
+	}
+
+	shanghai := time.FixedZone("CST", 8*60*60)
+	after := time.Now().Add(-time.Hour).In(shanghai)
+	before := time.Now().Add(time.Hour).In(shanghai)
+	resp, err := c.ListWorkflowLogs(ctx, &ListWorkflowLogsRequest{
+		Status:        WorkflowRunStatusSucceeded,
+		Page:          2,
+		Limit:         2,
+		CreatedAfter:  after,
+		CreatedBefore: before,
+		Token:         token,
+	})
+	if err != nil {
+		t.Fatal("Failed to list workflow logs:", err)
+	}
+	if !resp.IsSuccess() {
+		t.Fatal("List workflow logs failed:", resp.Message)
+	}
+
+	requests := server.Requests()
+	query := requests[len(requests)-1].Query
+	expected := map[string]string{
+		"status":             "succeeded",
+		"page":               "2",
+		"limit":              "2",
+		"created_at__after":  after.UTC().Format(time.RFC3339),
+		"created_at__before": before.UTC().Format(time.RFC3339),
+	}
+	for name, value := range expected {
+		if got := query.Get(name); got != value {
+			t.Errorf("Expected query %s=%s, got '%s'", name, value, got)
+		}
+	}
+	if _, ok := query["keyword"]; ok {
+		t.Error("Expected empty keyword to be omitted")
+	}
+
+	if resp.Result.Total != 3 || resp.Result.Page != 2 || resp.Result.HasMore {
+		t.Errorf("Unexpected pagination: %+v", resp.Result)
+	}
+	if len(resp.Result.Data) != 1 {
+		t.Fatalf("Expected 1 log on page 2, got %d", len(resp.Result.Data))
+	}
+	if log := resp.Result.Data[0]; log.WorkflowRun.Status != WorkflowRunStatusSucceeded || log.WorkflowRun.ID == "" {
+		t.Errorf("Unexpected workflow log: %+v", log)
+	}
+
+	resp, err = c.ListWorkflowLogs(ctx, &ListWorkflowLogsRequest{CreatedBefore: after, Token: token})
+	if err != nil {
+		t.Fatal("Failed to list workflow logs:", err)
+	}
+	if len(resp.Result.Data) != 0 {
+		t.Errorf("Expected no logs before %s, got %d", after, len(resp.Result.Data))
+	}
+}